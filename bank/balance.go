@@ -0,0 +1,436 @@
+package bank
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
+)
+
+// BalanceChange is the net change of one denom of a watched address in a block
+type BalanceChange struct {
+	Address string  `json:"address"`
+	Denom   string  `json:"denom"`
+	Delta   sdk.Int `json:"delta"`
+	Height  int64   `json:"height"`
+}
+
+// BalanceChangeHandler handles the balance changes of a block, called once per block with changes,
+// heights being delivered in order. It is called outside the locks of the watcher, so it may call
+// its methods, the next block is only processed once it returns.
+type BalanceChangeHandler func(changes []BalanceChange)
+
+// BalanceMismatch reports a balance tracked from the events that differs from the one queried from the chain
+type BalanceMismatch struct {
+	Address string  `json:"address"`
+	Denom   string  `json:"denom"`
+	Tracked sdk.Int `json:"tracked"`
+	Actual  sdk.Int `json:"actual"`
+	Height  int64   `json:"height"`
+}
+
+// BalanceMismatchHandler handles a reconciliation mismatch, called outside the locks of the watcher
+// once the tracked balance is reset to the actual one
+type BalanceMismatchHandler func(BalanceMismatch)
+
+// BalanceWatchConfig configures a BalanceWatcher
+type BalanceWatchConfig struct {
+	Addresses []string
+	// StartHeight is the first height processed, defaults to the next block
+	StartHeight int64
+	// ReconcileInterval enables the periodic comparison of the tracked balances with
+	// the balances queried from the chain, 0 disables it
+	ReconcileInterval time.Duration
+	// OnMismatch is called for every balance found out of sync during reconciliation
+	OnMismatch BalanceMismatchHandler
+}
+
+// BalanceWatcher streams the balance changes of a set of addresses, derived from the bank events of
+// begin block, every tx and end block, so rewards, mints, burns, slashing refunds and module
+// transfers are reported like plain sends.
+type BalanceWatcher struct {
+	client  sdk.BaseClient
+	cfg     BalanceWatchConfig
+	handler BalanceChangeHandler
+
+	// mtx guards addresses
+	mtx       sync.RWMutex
+	addresses map[string]bool
+
+	// processMtx serializes block processing and reconciliation, it guards height and tracked
+	processMtx sync.Mutex
+	height     int64
+	tracked    map[string]map[string]sdk.Int
+
+	subscription sdk.Subscription
+	notify       chan int64
+	quit         chan struct{}
+	stopOnce     sync.Once
+}
+
+// SubscribeBalanceChanges starts streaming the balance changes of the configured addresses
+func (b bankClient) SubscribeBalanceChanges(cfg BalanceWatchConfig, handler BalanceChangeHandler) (*BalanceWatcher, sdk.Error) {
+	if handler == nil {
+		return nil, sdk.Wrapf("handler is required")
+	}
+
+	w := &BalanceWatcher{
+		client:    b.BaseClient,
+		cfg:       cfg,
+		handler:   handler,
+		addresses: make(map[string]bool, len(cfg.Addresses)),
+		tracked:   make(map[string]map[string]sdk.Int),
+		notify:    make(chan int64, 1),
+		quit:      make(chan struct{}),
+	}
+	if err := w.AddAddresses(cfg.Addresses...); err != nil {
+		return nil, err
+	}
+
+	w.height = cfg.StartHeight - 1
+	if cfg.StartHeight <= 0 {
+		status, err := b.Status(context.Background())
+		if err != nil {
+			return nil, sdk.Wrap(err)
+		}
+		w.height = status.SyncInfo.LatestBlockHeight
+	}
+
+	subscription, err := b.SubscribeNewBlock(nil, func(block sdk.EventDataNewBlock) {
+		w.signal(block.Block.Height)
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.subscription = subscription
+
+	go w.run()
+	return w, nil
+}
+
+// AddAddresses starts watching the given addresses
+func (w *BalanceWatcher) AddAddresses(addresses ...string) sdk.Error {
+	for _, addr := range addresses {
+		if _, err := sdk.AccAddressFromBech32(addr); err != nil {
+			return sdk.Wrapf("%s invalid address", addr)
+		}
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for _, addr := range addresses {
+		w.addresses[addr] = true
+	}
+	return nil
+}
+
+// RemoveAddresses stops watching the given addresses
+func (w *BalanceWatcher) RemoveAddresses(addresses ...string) {
+	w.mtx.Lock()
+	for _, addr := range addresses {
+		delete(w.addresses, addr)
+	}
+	w.mtx.Unlock()
+
+	w.processMtx.Lock()
+	defer w.processMtx.Unlock()
+	for _, addr := range addresses {
+		delete(w.tracked, addr)
+	}
+}
+
+// Watching returns whether the address is watched
+func (w *BalanceWatcher) Watching(address string) bool {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	return w.addresses[address]
+}
+
+// Height returns the last processed height
+func (w *BalanceWatcher) Height() int64 {
+	w.processMtx.Lock()
+	defer w.processMtx.Unlock()
+	return w.height
+}
+
+// Stop ends the stream
+func (w *BalanceWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.quit)
+		if err := w.client.Unsubscribe(w.subscription); err != nil {
+			w.client.Logger().Error("unsubscribe balance changes failed", "errMsg", err.Error())
+		}
+	})
+}
+
+// signal records the latest height without blocking the websocket, intermediate heights are
+// fetched on catch up anyway
+func (w *BalanceWatcher) signal(height int64) {
+	for {
+		select {
+		case w.notify <- height:
+			return
+		case pending := <-w.notify:
+			if pending > height {
+				height = pending
+			}
+		}
+	}
+}
+
+func (w *BalanceWatcher) run() {
+	var reconcile <-chan time.Time
+	if w.cfg.ReconcileInterval > 0 {
+		ticker := time.NewTicker(w.cfg.ReconcileInterval)
+		defer ticker.Stop()
+		reconcile = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.quit:
+			return
+		case height := <-w.notify:
+			if err := w.catchUp(height); err != nil {
+				w.client.Logger().Error("process balance changes failed", "height", height, "errMsg", err.Error())
+			}
+		case <-reconcile:
+			if err := w.reconcile(); err != nil {
+				w.client.Logger().Error("reconcile balances failed", "errMsg", err.Error())
+			}
+		}
+	}
+}
+
+// catchUp processes every height up to target, a failed height is retried on the next block. The
+// changes of each block are handed to the handler once the processing lock is released.
+func (w *BalanceWatcher) catchUp(target int64) error {
+	for {
+		select {
+		case <-w.quit:
+			return nil
+		default:
+		}
+
+		changes, processed, err := w.processNext(target)
+		if err != nil || !processed {
+			return err
+		}
+		if len(changes) > 0 {
+			w.handler(changes)
+		}
+	}
+}
+
+// processNext applies the changes of the height following the processed one, when not above target
+func (w *BalanceWatcher) processNext(target int64) ([]BalanceChange, bool, error) {
+	w.processMtx.Lock()
+	defer w.processMtx.Unlock()
+
+	if w.height >= target {
+		return nil, false, nil
+	}
+	height := w.height + 1
+	res, err := w.client.BlockResults(context.Background(), &height)
+	if err != nil {
+		return nil, false, err
+	}
+	changes, err := ParseBalanceChanges(sdk.ParseBlockResult(res), w.Watching)
+	if err != nil {
+		return nil, false, err
+	}
+
+	w.apply(changes)
+	w.height = height
+	return changes, true, nil
+}
+
+func (w *BalanceWatcher) apply(changes []BalanceChange) {
+	for _, c := range changes {
+		balances, ok := w.tracked[c.Address]
+		if !ok {
+			continue
+		}
+		amt, ok := balances[c.Denom]
+		if !ok {
+			amt = sdk.ZeroInt()
+		}
+		balances[c.Denom] = amt.Add(c.Delta)
+	}
+}
+
+// reconcile catches up with the chain and compares the tracked balances with the balances queried
+// at the last processed height; a balance seen for the first time becomes the baseline.
+func (w *BalanceWatcher) reconcile() error {
+	latest, err := w.latestHeight()
+	if err != nil {
+		return err
+	}
+	if err := w.catchUp(latest); err != nil {
+		return err
+	}
+
+	mismatches, err := w.compare()
+	for _, mismatch := range mismatches {
+		w.cfg.OnMismatch(mismatch)
+	}
+	return err
+}
+
+// compare resets the tracked balances to the chain and returns the mismatches when OnMismatch is set,
+// the blocks are only processed by the run loop so the height does not move meanwhile
+func (w *BalanceWatcher) compare() ([]BalanceMismatch, error) {
+	w.processMtx.Lock()
+	defer w.processMtx.Unlock()
+
+	w.mtx.RLock()
+	addresses := make([]string, 0, len(w.addresses))
+	for addr := range w.addresses {
+		addresses = append(addresses, addr)
+	}
+	w.mtx.RUnlock()
+	sort.Strings(addresses)
+
+	actual := make(map[string]sdk.Coins, len(addresses))
+	for _, addr := range addresses {
+		balances, err := w.queryBalances(addr, w.height)
+		if err != nil {
+			return nil, err
+		}
+		actual[addr] = balances
+	}
+
+	var mismatches []BalanceMismatch
+	for _, addr := range addresses {
+		tracked, ok := w.tracked[addr]
+		if !ok {
+			w.tracked[addr] = coinsToBalances(actual[addr])
+			continue
+		}
+
+		denoms := make(map[string]bool)
+		for denom := range tracked {
+			denoms[denom] = true
+		}
+		for _, coin := range actual[addr] {
+			denoms[coin.Denom] = true
+		}
+		for denom := range denoms {
+			trackedAmt, ok := tracked[denom]
+			if !ok {
+				trackedAmt = sdk.ZeroInt()
+			}
+			actualAmt := actual[addr].AmountOf(denom)
+			if trackedAmt.Equal(actualAmt) || w.cfg.OnMismatch == nil {
+				continue
+			}
+			mismatches = append(mismatches, BalanceMismatch{
+				Address: addr,
+				Denom:   denom,
+				Tracked: trackedAmt,
+				Actual:  actualAmt,
+				Height:  w.height,
+			})
+		}
+		w.tracked[addr] = coinsToBalances(actual[addr])
+	}
+	return mismatches, nil
+}
+
+func (w *BalanceWatcher) latestHeight() (int64, error) {
+	status, err := w.client.Status(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// queryBalances returns the balances of the address at the height, so the blocks committed while
+// querying are not mixed in
+func (w *BalanceWatcher) queryBalances(address string, height int64) (sdk.Coins, error) {
+	conn, err := w.client.GenConn()
+	if err != nil {
+		return nil, err
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), sdk.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+
+	var balances sdk.Coins
+	var nextKey []byte
+	for {
+		resp, err := NewQueryClient(conn).AllBalances(
+			ctx,
+			&QueryAllBalancesRequest{
+				Address:    address,
+				Pagination: &query.PageRequest{Key: nextKey},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, resp.Balances...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return balances, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+func coinsToBalances(coins sdk.Coins) map[string]sdk.Int {
+	balances := make(map[string]sdk.Int, len(coins))
+	for _, coin := range coins {
+		balances[coin.Denom] = coin.Amount
+	}
+	return balances
+}
+
+// ParseBalanceChanges nets the balance changes of the watched addresses in a block. Begin block,
+// every successful tx and end block are parsed separately, so the coinbase/burn fallback of
+// sdk.ParseBalanceMovements applies per phase.
+func ParseBalanceChanges(result sdk.BlockResult, watching func(string) bool) ([]BalanceChange, error) {
+	phases := []sdk.StringEvents{result.Results.BeginBlock.Events}
+	for _, tx := range result.Results.DeliverTx {
+		if tx.Code != 0 {
+			continue
+		}
+		phases = append(phases, tx.Events)
+	}
+	phases = append(phases, result.Results.EndBlock.Events)
+
+	var movements []sdk.CoinMovement
+	for _, events := range phases {
+		ms, err := sdk.ParseBalanceMovements(events)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, ms...)
+	}
+
+	var addresses []string
+	seen := make(map[string]bool)
+	for _, m := range movements {
+		if !seen[m.Address] && watching(m.Address) {
+			addresses = append(addresses, m.Address)
+		}
+		seen[m.Address] = true
+	}
+	sort.Strings(addresses)
+
+	var changes []BalanceChange
+	for _, addr := range addresses {
+		for _, delta := range sdk.SumCoinMovements(addr, movements) {
+			changes = append(changes, BalanceChange{
+				Address: addr,
+				Denom:   delta.Denom,
+				Delta:   delta.Amount,
+				Height:  result.Height,
+			})
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,106 @@
+package bank
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+func TestParseBalanceChanges(t *testing.T) {
+	results := &ctypes.ResultBlockResults{
+		Height: 7,
+		BeginBlockEvents: []abci.Event{
+			// mint emits coinbase next to coin_received, it must be counted once
+			coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "mint", "100uiris"),
+			coinEvent(sdk.EventTypeCoinbase, sdk.AttributeKeyMinter, "mint", "100uiris"),
+			coinEvent(sdk.EventTypeCoinSpent, sdk.AttributeKeySpender, "mint", "100uiris"),
+			coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "fee_collector", "100uiris"),
+		},
+		TxsResults: []*abci.ResponseDeliverTx{
+			{
+				Events: []abci.Event{
+					coinEvent(sdk.EventTypeCoinSpent, sdk.AttributeKeySpender, "alice", "10uiris"),
+					coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "bob", "10uiris"),
+				},
+			},
+			{
+				Code:   5,
+				Events: []abci.Event{coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "alice", "1uiris")},
+			},
+		},
+		EndBlockEvents: []abci.Event{
+			// burn without coin_spent is still accounted for
+			coinEvent(sdk.EventTypeBurn, sdk.AttributeKeyBurner, "fee_collector", "3uiris"),
+		},
+	}
+	watched := map[string]bool{"mint": true, "fee_collector": true, "alice": true}
+
+	changes, err := ParseBalanceChanges(sdk.ParseBlockResult(results), func(addr string) bool { return watched[addr] })
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	require.Equal(t, "alice", changes[0].Address)
+	require.Equal(t, "uiris", changes[0].Denom)
+	require.True(t, changes[0].Delta.Equal(sdk.NewInt(-10)))
+	require.Equal(t, int64(7), changes[0].Height)
+
+	require.Equal(t, "fee_collector", changes[1].Address)
+	require.True(t, changes[1].Delta.Equal(sdk.NewInt(97)))
+}
+
+// blockResultsClient serves the block results of the watcher, the other methods are not used
+type blockResultsClient struct {
+	sdk.BaseClient
+	results func(height int64) *ctypes.ResultBlockResults
+}
+
+func (c blockResultsClient) BlockResults(_ context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	return c.results(*height), nil
+}
+
+func TestBalanceHandlerCallsWatcher(t *testing.T) {
+	addr := sdk.AccAddress("watched_address_____").String()
+	client := blockResultsClient{results: func(height int64) *ctypes.ResultBlockResults {
+		return &ctypes.ResultBlockResults{
+			Height: height,
+			TxsResults: []*abci.ResponseDeliverTx{{
+				Events: []abci.Event{coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, addr, "1uiris")},
+			}},
+		}
+	}}
+
+	heights := make(chan int64, 2)
+	w := &BalanceWatcher{
+		client:    client,
+		addresses: map[string]bool{addr: true},
+		tracked:   make(map[string]map[string]sdk.Int),
+		notify:    make(chan int64, 1),
+		quit:      make(chan struct{}),
+	}
+	w.handler = func(changes []BalanceChange) {
+		heights <- w.Height()
+		w.RemoveAddresses(addr)
+	}
+	defer close(w.quit)
+	go w.run()
+
+	w.signal(2)
+	select {
+	case height := <-heights:
+		// the handler sees the watcher once the changes of the block are applied
+		require.Equal(t, int64(1), height)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler deadlocked")
+	}
+	select {
+	case <-heights:
+		t.Fatal("changes of a removed address delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -166,7 +166,18 @@ func (b bankClient) SendBatch(sender sdk.AccAddress, request MultiSendRequest, b
 }
 
 // SubscribeSendTx Subscribe MsgSend event and return subscription
+//
+// Deprecated: the failure of the subscription is only logged, use SubscribeMsgSend or a DepositDetector
 func (b bankClient) SubscribeSendTx(from, to string, callback EventMsgSendCallback) sdk.Subscription {
+	subscription, err := b.SubscribeMsgSend(from, to, callback)
+	if err != nil {
+		b.Logger().Error("subscribe send tx failed", "errMsg", err.Error())
+	}
+	return subscription
+}
+
+// SubscribeMsgSend subscribes to the MsgSend from and to the addresses, an empty address matches any
+func (b bankClient) SubscribeMsgSend(from, to string, callback EventMsgSendCallback) (sdk.Subscription, sdk.Error) {
 	var builder = sdk.NewEventQueryBuilder()
 
 	from = strings.TrimSpace(from)
@@ -180,7 +191,7 @@ func (b bankClient) SubscribeSendTx(from, to string, callback EventMsgSendCallba
 		builder.AddCondition(sdk.Cond("transfer.recipient").EQ(sdk.EventValue(to)))
 	}
 
-	subscription, _ := b.SubscribeTx(builder, func(data sdk.EventDataTx) {
+	return b.SubscribeTx(builder, func(data sdk.EventDataTx) {
 		for _, msg := range data.Tx.GetMsgs() {
 			if value, ok := msg.(*MsgSend); ok {
 				callback(EventDataMsgSend{
@@ -193,5 +204,4 @@ func (b bankClient) SubscribeSendTx(from, to string, callback EventMsgSendCallba
 			}
 		}
 	})
-	return subscription
 }
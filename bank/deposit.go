@@ -0,0 +1,430 @@
+package bank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	typetx "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+const (
+	defaultDepositPollInterval = 3 * time.Second
+	depositCheckpointDBName    = "deposit"
+)
+
+var depositCheckpointKey = []byte("deposit_checkpoint")
+
+// DepositCheckpoint records the progress of a DepositDetector.
+// Height is the next height to scan and Delivered the IDs of the deposits of that height
+// already handed to the callback, so the watched addresses can change across a restart.
+type DepositCheckpoint struct {
+	Height    int64    `json:"height"`
+	Delivered []string `json:"delivered,omitempty"`
+}
+
+// DepositCheckpointStore persists the progress of a DepositDetector so that it can resume after a restart
+type DepositCheckpointStore interface {
+	Load() (checkpoint DepositCheckpoint, found bool, err error)
+	Save(checkpoint DepositCheckpoint) error
+	// Close releases the store, it is closed by its owner once the detector is stopped
+	Close() error
+}
+
+type memoryCheckpointStore struct {
+	mtx        sync.Mutex
+	checkpoint *DepositCheckpoint
+}
+
+// NewMemoryCheckpointStore returns a checkpoint store that does not survive restarts, use with caution in production
+func NewMemoryCheckpointStore() DepositCheckpointStore {
+	return &memoryCheckpointStore{}
+}
+
+func (m *memoryCheckpointStore) Load() (DepositCheckpoint, bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.checkpoint == nil {
+		return DepositCheckpoint{}, false, nil
+	}
+	return *m.checkpoint, true, nil
+}
+
+func (m *memoryCheckpointStore) Save(checkpoint DepositCheckpoint) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.checkpoint = &checkpoint
+	return nil
+}
+
+func (m *memoryCheckpointStore) Close() error {
+	return nil
+}
+
+type levelDBCheckpointStore struct {
+	db dbm.DB
+}
+
+// NewLevelDBCheckpointStore returns a checkpoint store persisted in a leveldb under rootDir
+func NewLevelDBCheckpointStore(rootDir string) (DepositCheckpointStore, error) {
+	db, err := dbm.NewGoLevelDB(depositCheckpointDBName, filepath.Join(rootDir, depositCheckpointDBName))
+	if err != nil {
+		return nil, err
+	}
+	return levelDBCheckpointStore{db: db}, nil
+}
+
+func (l levelDBCheckpointStore) Load() (checkpoint DepositCheckpoint, found bool, err error) {
+	bz, err := l.db.Get(depositCheckpointKey)
+	if bz == nil || err != nil {
+		return checkpoint, false, err
+	}
+	if err := json.Unmarshal(bz, &checkpoint); err != nil {
+		return checkpoint, false, err
+	}
+	return checkpoint, true, nil
+}
+
+func (l levelDBCheckpointStore) Save(checkpoint DepositCheckpoint) error {
+	bz, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return l.db.SetSync(depositCheckpointKey, bz)
+}
+
+func (l levelDBCheckpointStore) Close() error {
+	return l.db.Close()
+}
+
+// DepositDetectorConfig configures a DepositDetector
+type DepositDetectorConfig struct {
+	// Addresses are the deposit addresses to watch. For memo based routing watch a single
+	// address and dispatch on Deposit.Memo.
+	Addresses []string
+	// Confirmations is the number of blocks (the inclusion block counted) required before
+	// a deposit is emitted, defaults to 1
+	Confirmations int64
+	// StartHeight is the first height scanned when the checkpoint store is empty,
+	// defaults to the latest height
+	StartHeight int64
+	// PollInterval is the interval between two scans of the chain, defaults to 3s
+	PollInterval time.Duration
+	// Checkpoint persists the progress, defaults to an in-memory store
+	Checkpoint DepositCheckpointStore
+}
+
+// Deposit is a credit of a watched address, extracted from the coin_received events of a successful tx.
+// It covers every way funds can arrive: MsgSend, MsgMultiSend, IBC receives, authz exec, ...
+type Deposit struct {
+	// ID is stable across restarts, consumers can use it as an idempotency key
+	ID      string    `json:"id"`
+	Height  int64     `json:"height"`
+	TxHash  string    `json:"tx_hash"`
+	TxIndex uint32    `json:"tx_index"`
+	Address string    `json:"address"`
+	Amount  sdk.Coins `json:"amount"`
+	// Senders are the accounts that spent coins in the tx, the fee payer included
+	Senders []string `json:"senders"`
+	Memo    string   `json:"memo"`
+
+	// credit is the position of the deposit among all the credits of its tx
+	credit int
+}
+
+// DepositCallback handles a confirmed deposit. When it returns an error, the same deposit
+// is delivered again on the next scan, and no later deposit is delivered before it succeeds.
+type DepositCallback func(Deposit) error
+
+// DepositDetector scans the chain block by block and delivers every confirmed deposit
+// to the callback exactly once, the checkpoint being saved after each delivery.
+// If the process dies between a successful callback and the checkpoint write, that single
+// deposit is delivered again after the restart with the same ID.
+type DepositDetector struct {
+	client   sdk.BaseClient
+	cfg      DepositDetectorConfig
+	callback DepositCallback
+
+	// mtx guards addresses and checkpoint
+	mtx       sync.RWMutex
+	addresses map[string]bool
+
+	checkpoint DepositCheckpoint
+	quit       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewDepositDetector creates a deposit detector, call Start to begin scanning
+func (b bankClient) NewDepositDetector(cfg DepositDetectorConfig, callback DepositCallback) (*DepositDetector, sdk.Error) {
+	if callback == nil {
+		return nil, sdk.Wrapf("callback is required")
+	}
+	if cfg.Confirmations <= 0 {
+		cfg.Confirmations = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultDepositPollInterval
+	}
+	if cfg.Checkpoint == nil {
+		cfg.Checkpoint = NewMemoryCheckpointStore()
+	}
+
+	d := &DepositDetector{
+		client:    b.BaseClient,
+		cfg:       cfg,
+		callback:  callback,
+		addresses: make(map[string]bool, len(cfg.Addresses)),
+		quit:      make(chan struct{}),
+	}
+	if err := d.AddAddresses(cfg.Addresses...); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// AddAddresses starts watching the given addresses
+func (d *DepositDetector) AddAddresses(addresses ...string) sdk.Error {
+	for _, addr := range addresses {
+		if _, err := sdk.AccAddressFromBech32(addr); err != nil {
+			return sdk.Wrapf("%s invalid address", addr)
+		}
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, addr := range addresses {
+		d.addresses[addr] = true
+	}
+	return nil
+}
+
+// RemoveAddresses stops watching the given addresses
+func (d *DepositDetector) RemoveAddresses(addresses ...string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, addr := range addresses {
+		delete(d.addresses, addr)
+	}
+}
+
+// Watching returns whether the address is watched
+func (d *DepositDetector) Watching(address string) bool {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	return d.addresses[address]
+}
+
+// Checkpoint returns the current progress of the detector
+func (d *DepositDetector) Checkpoint() DepositCheckpoint {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	return d.checkpoint
+}
+
+// Start loads the checkpoint and scans the chain in the background until Stop is called
+func (d *DepositDetector) Start() sdk.Error {
+	checkpoint, found, err := d.cfg.Checkpoint.Load()
+	if err != nil {
+		return sdk.Wrap(err)
+	}
+
+	if !found {
+		checkpoint = DepositCheckpoint{Height: d.cfg.StartHeight}
+		if checkpoint.Height <= 0 {
+			status, err := d.client.Status(context.Background())
+			if err != nil {
+				return sdk.Wrap(err)
+			}
+			checkpoint.Height = status.SyncInfo.LatestBlockHeight
+		}
+		if err := d.cfg.Checkpoint.Save(checkpoint); err != nil {
+			return sdk.Wrap(err)
+		}
+	}
+	d.checkpoint = checkpoint
+
+	d.client.Logger().Info("start deposit detector", "height", checkpoint.Height, "delivered", len(checkpoint.Delivered))
+	go d.run()
+	return nil
+}
+
+// Stop ends the background scan
+func (d *DepositDetector) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.quit)
+	})
+}
+
+func (d *DepositDetector) run() {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.scan(); err != nil {
+			d.client.Logger().Error("scan deposits failed", "height", d.checkpoint.Height, "errMsg", err.Error())
+		}
+
+		select {
+		case <-d.quit:
+			d.client.Logger().Info("end deposit detector", "height", d.checkpoint.Height)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan processes every height that reached the required number of confirmations
+func (d *DepositDetector) scan() error {
+	status, err := d.client.Status(context.Background())
+	if err != nil {
+		return err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+
+	for d.checkpoint.Height+d.cfg.Confirmations-1 <= latest {
+		select {
+		case <-d.quit:
+			return nil
+		default:
+		}
+		if err := d.processHeight(d.checkpoint.Height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DepositDetector) processHeight(height int64) error {
+	results, err := d.client.BlockResults(context.Background(), &height)
+	if err != nil {
+		return err
+	}
+
+	deposits, err := extractDeposits(results, d.Watching)
+	if err != nil {
+		return err
+	}
+
+	if len(deposits) > 0 {
+		block, err := d.client.Block(context.Background(), &height)
+		if err != nil {
+			return err
+		}
+		fillDeposits(deposits, block)
+	}
+
+	var delivered []string
+	if d.checkpoint.Height == height {
+		delivered = append(delivered, d.checkpoint.Delivered...)
+	}
+	skip := make(map[string]bool, len(delivered))
+	for _, id := range delivered {
+		skip[id] = true
+	}
+
+	for _, deposit := range deposits {
+		if skip[deposit.ID] {
+			continue
+		}
+		if err := d.callback(deposit); err != nil {
+			return fmt.Errorf("deposit %s callback failed: %s", deposit.ID, err.Error())
+		}
+		delivered = append(delivered, deposit.ID)
+		checkpoint := DepositCheckpoint{Height: height, Delivered: append([]string(nil), delivered...)}
+		if err := d.save(checkpoint); err != nil {
+			return err
+		}
+	}
+	return d.save(DepositCheckpoint{Height: height + 1})
+}
+
+func (d *DepositDetector) save(checkpoint DepositCheckpoint) error {
+	if err := d.cfg.Checkpoint.Save(checkpoint); err != nil {
+		return err
+	}
+	d.mtx.Lock()
+	d.checkpoint = checkpoint
+	d.mtx.Unlock()
+	return nil
+}
+
+// extractDeposits returns the credits of the watched addresses in the successful txs of the block, in a deterministic order
+func extractDeposits(results *ctypes.ResultBlockResults, watching func(string) bool) ([]Deposit, error) {
+	var deposits []Deposit
+	for i, txResult := range results.TxsResults {
+		if txResult.Code != 0 {
+			continue
+		}
+
+		movements, err := sdk.ParseCoinMovements(sdk.StringifyEvents(txResult.Events))
+		if err != nil {
+			return nil, err
+		}
+
+		var senders []string
+		seen := make(map[string]bool)
+		for _, m := range movements {
+			if !m.Credit && !seen[m.Address] {
+				senders = append(senders, m.Address)
+				seen[m.Address] = true
+			}
+		}
+
+		credit := -1
+		for _, m := range movements {
+			if !m.Credit {
+				continue
+			}
+			credit++
+			if !watching(m.Address) {
+				continue
+			}
+			deposits = append(deposits, Deposit{
+				Height:  results.Height,
+				TxIndex: uint32(i),
+				Address: m.Address,
+				Amount:  m.Coins,
+				Senders: senders,
+				credit:  credit,
+			})
+		}
+	}
+	return deposits, nil
+}
+
+// fillDeposits completes the deposits with the hash and memo of their tx, the ID counts every
+// credit of the tx so it does not depend on the watched addresses
+func fillDeposits(deposits []Deposit, block *ctypes.ResultBlock) {
+	for i := range deposits {
+		idx := deposits[i].TxIndex
+		if int(idx) >= len(block.Block.Txs) {
+			continue
+		}
+		tx := block.Block.Txs[idx]
+		deposits[i].TxHash = sdk.HexBytes(tmhash.Sum(tx)).String()
+		deposits[i].Memo = txMemo(tx)
+		deposits[i].ID = fmt.Sprintf("%s/%d", deposits[i].TxHash, deposits[i].credit)
+	}
+}
+
+// txMemo reads the memo without resolving the msgs, so txs containing msg types unknown
+// to the sdk do not block the detector
+func txMemo(txBytes []byte) string {
+	var raw typetx.TxRaw
+	if err := raw.Unmarshal(txBytes); err != nil {
+		return ""
+	}
+	var body typetx.TxBody
+	if err := body.Unmarshal(raw.BodyBytes); err != nil {
+		return ""
+	}
+	return body.Memo
+}
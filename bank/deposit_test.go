@@ -0,0 +1,116 @@
+package bank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	typetx "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+func coinEvent(typ, addrKey, addr, amount string) abci.Event {
+	return abci.Event{
+		Type: typ,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(addrKey), Value: []byte(addr)},
+			{Key: []byte(sdk.AttributeKeyAmount), Value: []byte(amount)},
+		},
+	}
+}
+
+func TestExtractDeposits(t *testing.T) {
+	results := &ctypes.ResultBlockResults{
+		Height: 10,
+		TxsResults: []*abci.ResponseDeliverTx{
+			{
+				// multi send to a watched and an unwatched address
+				Events: []abci.Event{
+					coinEvent(sdk.EventTypeCoinSpent, sdk.AttributeKeySpender, "alice", "2uiris"),
+					coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "fee_collector", "2uiris"),
+					coinEvent(sdk.EventTypeCoinSpent, sdk.AttributeKeySpender, "alice", "30uiris"),
+					coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit1", "10uiris"),
+					coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "bob", "10uiris"),
+					coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit1", "10uiris"),
+				},
+			},
+			{
+				// failed tx must be ignored
+				Code:   5,
+				Events: []abci.Event{coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit2", "1uiris")},
+			},
+			{
+				Events: []abci.Event{coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit2", "7ibc/ABCD")},
+			},
+		},
+	}
+	watched := map[string]bool{"deposit1": true, "deposit2": true}
+
+	deposits, err := extractDeposits(results, func(addr string) bool { return watched[addr] })
+	require.NoError(t, err)
+	require.Len(t, deposits, 3)
+	require.Equal(t, "deposit1", deposits[0].Address)
+	require.Equal(t, []string{"alice"}, deposits[0].Senders)
+	require.Equal(t, uint32(2), deposits[2].TxIndex)
+	require.Equal(t, "7ibc/ABCD", deposits[2].Amount.String())
+
+	body := typetx.TxBody{Memo: "user-42"}
+	bodyBz, err := body.Marshal()
+	require.NoError(t, err)
+	raw := typetx.TxRaw{BodyBytes: bodyBz}
+	txBz, err := raw.Marshal()
+	require.NoError(t, err)
+
+	block := &ctypes.ResultBlock{Block: &tmtypes.Block{Data: tmtypes.Data{Txs: tmtypes.Txs{txBz, []byte("x"), []byte("y")}}}}
+	fillDeposits(deposits, block)
+	require.Equal(t, "user-42", deposits[0].Memo)
+	// the credits of fee_collector and bob are counted
+	require.Equal(t, deposits[0].TxHash+"/1", deposits[0].ID)
+	require.Equal(t, deposits[1].TxHash+"/3", deposits[1].ID)
+	require.Equal(t, deposits[2].TxHash+"/0", deposits[2].ID)
+	require.Empty(t, deposits[2].Memo)
+}
+
+func TestDepositIDsIgnoreWatchedAddresses(t *testing.T) {
+	results := &ctypes.ResultBlockResults{
+		Height: 10,
+		TxsResults: []*abci.ResponseDeliverTx{{
+			Events: []abci.Event{
+				coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit2", "5uiris"),
+				coinEvent(sdk.EventTypeCoinReceived, sdk.AttributeKeyReceiver, "deposit1", "10uiris"),
+			},
+		}},
+	}
+	block := &ctypes.ResultBlock{Block: &tmtypes.Block{Data: tmtypes.Data{Txs: tmtypes.Txs{[]byte("x")}}}}
+
+	// deposit2 is removed between a crash and the restart
+	before, err := extractDeposits(results, func(addr string) bool { return addr == "deposit1" || addr == "deposit2" })
+	require.NoError(t, err)
+	fillDeposits(before, block)
+	after, err := extractDeposits(results, func(addr string) bool { return addr == "deposit1" })
+	require.NoError(t, err)
+	fillDeposits(after, block)
+
+	require.Len(t, after, 1)
+	require.Equal(t, before[1].ID, after[0].ID)
+}
+
+func TestLevelDBCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLevelDBCheckpointStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(DepositCheckpoint{Height: 7, Delivered: []string{"a"}}))
+	require.NoError(t, store.Close())
+
+	// the closed store releases the lock of its db
+	store, err = NewLevelDBCheckpointStore(dir)
+	require.NoError(t, err)
+	defer store.Close()
+	checkpoint, found, err := store.Load()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, DepositCheckpoint{Height: 7, Delivered: []string{"a"}}, checkpoint)
+}
@@ -2,6 +2,7 @@ package bank
 
 import (
 	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
 )
 
 // expose bank module api for user
@@ -11,8 +12,15 @@ type Client interface {
 	SendWitchSpecAccountInfo(to string, sequence, accountNumber uint64, amount sdk.DecCoins, baseTx sdk.BaseTx) (sdk.ResultTx, sdk.Error)
 	MultiSend(receipts MultiSendRequest, baseTx sdk.BaseTx) ([]sdk.ResultTx, sdk.Error)
 	SubscribeSendTx(from, to string, callback EventMsgSendCallback) sdk.Subscription
+	SubscribeMsgSend(from, to string, callback EventMsgSendCallback) (sdk.Subscription, sdk.Error)
+	NewDepositDetector(cfg DepositDetectorConfig, callback DepositCallback) (*DepositDetector, sdk.Error)
+	SubscribeBalanceChanges(cfg BalanceWatchConfig, handler BalanceChangeHandler) (*BalanceWatcher, sdk.Error)
 	QueryAccount(address string) (sdk.BaseAccount, sdk.Error)
 	TotalSupply() (sdk.Coins, sdk.Error)
+
+	// the Iterate variants follow the next keys of the pages, the All variants drain them
+	IterateAllBalances(address string, opts query.IterateOptions) *query.PageIterator
+	AllBalances(address string) (sdk.Coins, sdk.Error)
 }
 
 type Receipt struct {
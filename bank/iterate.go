@@ -0,0 +1,44 @@
+package bank
+
+import (
+	"context"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
+)
+
+// IterateAllBalances iterates the balances of the address, sdk.Coin items
+func (b bankClient) IterateAllBalances(address string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := b.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).AllBalances(
+			context.Background(),
+			&QueryAllBalancesRequest{Address: address, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Balances))
+		for i, coin := range res.Balances {
+			items[i] = coin
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllBalances returns every balance of the address
+func (b bankClient) AllBalances(address string) (sdk.Coins, sdk.Error) {
+	items, err := b.IterateAllBalances(address, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	balances := make(sdk.Coins, len(items))
+	for i, item := range items {
+		balances[i] = item.(sdk.Coin)
+	}
+	return balances, nil
+}
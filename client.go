@@ -1,6 +1,8 @@
 package sdk
 
 import (
+	"fmt"
+
 	"github.com/irisnet/core-sdk-go/feegrant"
 	"github.com/tendermint/tendermint/libs/log"
 
@@ -64,6 +66,42 @@ func NewClient(cfg types.ClientConfig) Client {
 	return client
 }
 
+// WithHeight returns a copy of the client whose module queries are served at the height, see
+// BaseClient.WithHeight. The height each response was served at is returned by ServedHeight.
+// The modules added with RegisterModule are rebuilt on the copy, they must be HeightModules.
+func (client *Client) WithHeight(height int64) (Client, error) {
+	base, err := client.BaseClient.WithHeight(height)
+	if err != nil {
+		return Client{}, err
+	}
+
+	pinned := *client
+	pinned.BaseClient = base
+	marshaler := client.encodingConfig.Marshaler
+	pinned.Bank = bank.NewClient(pinned.BaseClient, marshaler)
+	pinned.Staking = staking.NewClient(pinned.BaseClient, marshaler)
+	pinned.Gov = gov.NewClient(pinned.BaseClient, marshaler)
+	pinned.Transfer = transfer.NewClient(pinned.BaseClient, marshaler)
+	pinned.FeeGrant = feegrant.NewClient(pinned.BaseClient, marshaler)
+
+	// the registry holds the pinned modules, the ones registered on the client query at its height
+	pinned.moduleManager = make(map[string]types.Module, len(client.moduleManager))
+	for _, m := range []types.Module{pinned.Bank, pinned.Staking, pinned.Gov, pinned.Transfer, pinned.FeeGrant} {
+		pinned.moduleManager[m.Name()] = m
+	}
+	for name, m := range client.moduleManager {
+		if _, ok := pinned.moduleManager[name]; ok {
+			continue
+		}
+		hm, ok := m.(types.HeightModule)
+		if !ok {
+			return Client{}, fmt.Errorf("the module %s can't be pinned to a height, it must be a HeightModule", name)
+		}
+		pinned.moduleManager[name] = hm.WithBaseClient(pinned.BaseClient)
+	}
+	return pinned, nil
+}
+
 func (client *Client) SetLogger(logger log.Logger) {
 	client.BaseClient.SetLogger(logger)
 }
@@ -87,6 +125,7 @@ func (client *Client) Manager() types.BaseClient {
 func (client *Client) RegisterModule(ms ...types.Module) {
 	for _, m := range ms {
 		m.RegisterInterfaceTypes(client.encodingConfig.InterfaceRegistry)
+		client.moduleManager[m.Name()] = m
 	}
 }
 
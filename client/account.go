@@ -12,6 +12,7 @@ import (
 	commoncodec "github.com/irisnet/core-sdk-go/common/codec"
 	sdk "github.com/irisnet/core-sdk-go/types"
 	"github.com/irisnet/core-sdk-go/types/auth"
+	"github.com/irisnet/core-sdk-go/types/query"
 )
 
 // Must be used with locker, otherwise there are thread safety issues
@@ -67,17 +68,24 @@ func (a AccountQuery) QueryAccount(address string) (sdk.BaseAccount, sdk.Error)
 	a2 := baseAccount.(auth.BaseAccountI)
 	account := a2.ConvertAccount(a.cdc).(sdk.BaseAccount)
 
-	breq := &bank.QueryAllBalancesRequest{
-		Address:    address,
-		Pagination: nil,
-	}
-	balances, err := bank.NewQueryClient(conn).AllBalances(context.Background(), breq)
-	if err != nil {
-		return sdk.BaseAccount{}, sdk.Wrap(err)
-	}
+	// the balances are paginated, past a page of denoms they are followed by the next keys
+	var nextKey []byte
+	for {
+		breq := &bank.QueryAllBalancesRequest{
+			Address:    address,
+			Pagination: &query.PageRequest{Key: nextKey, Limit: query.MaxPageLimit},
+		}
+		balances, err := bank.NewQueryClient(conn).AllBalances(context.Background(), breq)
+		if err != nil {
+			return sdk.BaseAccount{}, sdk.Wrap(err)
+		}
 
-	account.Coins = balances.Balances
-	return account, nil
+		account.Coins = append(account.Coins, balances.Balances...)
+		if balances.Pagination == nil || len(balances.Pagination.NextKey) == 0 {
+			return account, nil
+		}
+		nextKey = balances.Pagination.NextKey
+	}
 }
 
 func (a AccountQuery) QueryAddress(name, password string) (sdk.AccAddress, sdk.Error) {
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAccountPagesBalances(t *testing.T) {
+	node := &cacheNode{height: 1, sequence: 4, denoms: 250}
+	base := newCachedClient(node, 0)
+
+	account, err := base.QueryAccount("addr")
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), account.Sequence)
+	require.Len(t, account.Coins, 250)
+	require.Equal(t, "denom249", account.Coins[249].Denom)
+	// the account and three pages of balances
+	require.Equal(t, 4, node.calls)
+}
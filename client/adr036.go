@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
+	"github.com/irisnet/core-sdk-go/types"
+)
+
+// SignArbitrary signs off-chain data, e.g. a login challenge, with the ADR-036 sign doc of the key address
+func (k keysClient) SignArbitrary(name, password string, data []byte) (types.ArbitrarySignature, types.Error) {
+	_, address, err := k.KeyManager.Find(name, password)
+	if err != nil {
+		return types.ArbitrarySignature{}, types.Wrap(err)
+	}
+
+	signature, pubKey, err := k.KeyManager.Sign(name, password, types.ADR036SignBytes(address.String(), data))
+	if err != nil {
+		return types.ArbitrarySignature{}, types.Wrap(err)
+	}
+
+	aminoPubKey, err := ToAminoPubKey(pubKey)
+	if err != nil {
+		return types.ArbitrarySignature{}, types.Wrap(err)
+	}
+	return types.ArbitrarySignature{PubKey: aminoPubKey, Signature: signature}, nil
+}
+
+func (k keysClient) VerifyArbitrary(address string, pubKey tmcrypto.PubKey, data, signature []byte) types.Error {
+	return types.Wrap(VerifyArbitrary(address, pubKey, data, signature))
+}
+
+// VerifyArbitrary verifies the ADR-036 signature of off-chain data, the public key must belong to the address
+func VerifyArbitrary(address string, pubKey tmcrypto.PubKey, data, signature []byte) error {
+	if pubKey == nil {
+		return fmt.Errorf("missing public key")
+	}
+
+	accAddress, err := types.AccAddressFromBech32(address)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(accAddress, pubKey.Address()) {
+		return fmt.Errorf("the public key does not match the address %s", address)
+	}
+
+	if !pubKey.VerifySignature(types.ADR036SignBytes(address, data), signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// ToAminoPubKey returns the amino JSON of a secp256k1, sm2, eth_secp256k1 or ed25519 public key
+func ToAminoPubKey(pubKey tmcrypto.PubKey) (types.AminoPubKey, error) {
+	switch pubKey.(type) {
+	case *secp256k1.PubKey:
+		return types.AminoPubKey{Type: secp256k1.PubKeyName, Value: pubKey.Bytes()}, nil
+	case *sm2.PubKey:
+		return types.AminoPubKey{Type: sm2.PubKeyName, Value: pubKey.Bytes()}, nil
+	case *ethsecp256k1.PubKey:
+		return types.AminoPubKey{Type: ethsecp256k1.PubKeyName, Value: pubKey.Bytes()}, nil
+	case *ed25519.PubKey:
+		return types.AminoPubKey{Type: ed25519.PubKeyName, Value: pubKey.Bytes()}, nil
+	default:
+		return types.AminoPubKey{}, fmt.Errorf("unsupported public key type %T", pubKey)
+	}
+}
+
+// FromAminoPubKey returns the public key of an amino JSON, e.g. the pub_key of Keplr's signArbitrary
+func FromAminoPubKey(pubKey types.AminoPubKey) (tmcrypto.PubKey, error) {
+	switch pubKey.Type {
+	case secp256k1.PubKeyName:
+		return &secp256k1.PubKey{Key: pubKey.Value}, nil
+	case sm2.PubKeyName:
+		return &sm2.PubKey{Key: pubKey.Value}, nil
+	case ethsecp256k1.PubKeyName:
+		return &ethsecp256k1.PubKey{Key: pubKey.Value}, nil
+	case ed25519.PubKeyName:
+		return &ed25519.PubKey{Key: pubKey.Value}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %s", pubKey.Type)
+	}
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/common/bech32"
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestADR036SignBytes(t *testing.T) {
+	signBytes := types.ADR036SignBytes("iaa1signer", []byte("login<&>"))
+	require.Equal(t,
+		`{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"",`+
+			`"msgs":[{"type":"sign/MsgSignData","value":{"data":"bG9naW48Jj4=","signer":"iaa1signer"}}],"sequence":"0"}`,
+		string(signBytes),
+	)
+}
+
+// adr036Mnemonic is the test mnemonic of cosmjs, its first account is
+// cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4
+const adr036Mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestADR036Vectors(t *testing.T) {
+	// the sign doc of the example of ADR-036, as built by makeADR36AminoSignDoc and serializeSignDoc of cosmjs
+	require.Equal(t,
+		`{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"",`+
+			`"msgs":[{"type":"sign/MsgSignData","value":{"data":"cmFuZG9t","signer":"cosmos1hftz5ugqmpg9243xeegsqqav62f8hnywsjr4xr"}}],"sequence":"0"}`,
+		string(types.ADR036SignBytes("cosmos1hftz5ugqmpg9243xeegsqqav62f8hnywsjr4xr", []byte("random"))),
+	)
+
+	derived, err := hd.Secp256k1.Derive()(adr036Mnemonic, "", "m/44'/118'/0'/0/0")
+	require.NoError(t, err)
+	privKey := hd.Secp256k1.Generate()(derived)
+	pubKey := privKey.PubKey()
+
+	// the secp256k1 signatures are deterministic (RFC 6979), signing the same data for the same signer with
+	// the same key gives the same signature in any ADR-036 wallet
+	vectors := []struct {
+		signer    string
+		signature string
+	}{
+		{
+			signer:    "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4",
+			signature: "c1ClgHPIL7qB+W9zK9GeWOfxTgCklbYTPxjxU9klLR5OO2yYnLeSc0HRikPt2xT/lc6i58fSVUKaXJLtGKomEA==",
+		},
+		{
+			signer:    "iaa19rl4cm2hmr8afy4kldpxz3fka4jguq0afzdvay",
+			signature: "ATx+gWaf5BrV1O8ThkWUzzeyqyzjSkf8W/1+IL6o0w5+oNz/K9hlSoVGkhD2aVTcFvDyhsC9D0KVuZoB+qpL4g==",
+		},
+	}
+	data := []byte("Hello, ADR-036")
+	for _, vector := range vectors {
+		signature := mustDecodeBase64(t, vector.signature)
+		signed, err := privKey.Sign(types.ADR036SignBytes(vector.signer, data))
+		require.NoError(t, err)
+		require.Equal(t, signature, signed, vector.signer)
+		require.True(t, pubKey.VerifySignature(types.ADR036SignBytes(vector.signer, data), signature), vector.signer)
+	}
+
+	cosmosAddress, err := bech32.ConvertAndEncode("cosmos", pubKey.Address())
+	require.NoError(t, err)
+	require.Equal(t, vectors[0].signer, cosmosAddress)
+
+	// VerifyArbitrary checks the addresses of the configured prefix
+	require.NoError(t, VerifyArbitrary(vectors[1].signer, pubKey, data, mustDecodeBase64(t, vectors[1].signature)))
+	require.Error(t, VerifyArbitrary(vectors[1].signer, pubKey, []byte("random"), mustDecodeBase64(t, vectors[1].signature)))
+}
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	bz, err := base64.StdEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
+
+func TestSignArbitrary(t *testing.T) {
+	for _, algo := range []string{"secp256k1", "sm2", ethsecp256k1.KeyType} {
+		km := NewKeyManager(store.NewMemory(nil), algo)
+		address, _, err := km.Insert("login", "12345678")
+		require.NoError(t, err)
+
+		client := keysClient{BIP44Params: *hd.NewFundraiserParams(0, 118, 0), KeyManager: km}
+		signature, sdkErr := client.SignArbitrary("login", "12345678", []byte("challenge"))
+		require.NoError(t, sdkErr, algo)
+
+		// the signature round trips through the JSON returned by Keplr
+		bz, err := json.Marshal(signature)
+		require.NoError(t, err)
+		var decoded types.ArbitrarySignature
+		require.NoError(t, json.Unmarshal(bz, &decoded))
+
+		pubKey, err := FromAminoPubKey(decoded.PubKey)
+		require.NoError(t, err)
+		require.NoError(t, VerifyArbitrary(address, pubKey, []byte("challenge"), decoded.Signature), algo)
+		require.Error(t, VerifyArbitrary(address, pubKey, []byte("tampered"), decoded.Signature), algo)
+
+		other, _, err := km.Insert("other", "12345678")
+		require.NoError(t, err)
+		require.Error(t, client.VerifyArbitrary(other, pubKey, []byte("challenge"), decoded.Signature), algo)
+	}
+}
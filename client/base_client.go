@@ -21,6 +21,7 @@ import (
 	commoncache "github.com/irisnet/core-sdk-go/common/cache"
 	commoncodec "github.com/irisnet/core-sdk-go/common/codec"
 	sdklog "github.com/irisnet/core-sdk-go/common/log"
+	"github.com/irisnet/core-sdk-go/store"
 	sdktypes "github.com/irisnet/core-sdk-go/types"
 	"github.com/irisnet/core-sdk-go/types/tx"
 )
@@ -31,6 +32,9 @@ const (
 	cacheExpirePeriod = 1 * time.Minute
 	tryThreshold      = 3
 	maxBatch          = 100
+
+	blockTimeCacheCapacity = 1000
+	blockFetchConcurrency  = 8
 )
 
 type baseClient struct {
@@ -40,6 +44,13 @@ type baseClient struct {
 	cfg            *sdktypes.ClientConfig
 	encodingConfig sdktypes.EncodingConfig
 	l              *locker
+	blockTimes     commoncache.Cache
+	light          *lightClient
+	queryCache     *queryCache
+	reflection     *reflectionMethods
+	// the height of the queries of a client of WithHeight
+	height int64
+	served *servedHeight
 	AccountQuery
 }
 
@@ -63,17 +74,26 @@ func NewBaseClient(cfg sdktypes.ClientConfig, encodingConfig sdktypes.EncodingCo
 		cfg:            &cfg,
 		encodingConfig: encodingConfig,
 		l:              NewLocker(concurrency).setLogger(logger),
+		blockTimes:     commoncache.NewCache(blockTimeCacheCapacity, true),
+		light:          newLightClient(cfg, logger),
+		reflection:     &reflectionMethods{},
 		TokenManager:   cfg.TokenManager,
 	}
-	base.KeyManager = KeyManager{
-		KeyDAO: cfg.KeyDAO,
-		Algo:   cfg.Algo,
+	base.KeyManager = NewKeyManager(cfg.KeyDAO, cfg.Algo)
+	if cfg.Signer != nil {
+		base.KeyManager = newSignerKeyManager(cfg.Signer)
+	}
+
+	base.queryCache = newQueryCache(cfg, base.TmClient, logger)
+	grpcClient := NewGRPCClient(cfg.GRPCAddr, cfg.GRPCOptions...)
+	if base.queryCache != nil {
+		grpcClient = cachedGRPCClient{GRPCClient: grpcClient, cache: base.queryCache}
 	}
 
 	c := commoncache.NewCache(cacheCapacity, cfg.Cached)
 	base.AccountQuery = AccountQuery{
 		Queries:    base,
-		GRPCClient: NewGRPCClient(cfg.GRPCAddr, cfg.GRPCOptions...),
+		GRPCClient: grpcClient,
 		Logger:     logger,
 		Cache:      c,
 		cdc:        encodingConfig.Marshaler,
@@ -112,6 +132,19 @@ func (base *baseClient) BuildTxHash(msg []sdktypes.Msg, baseTx sdktypes.BaseTx)
 	return strings.ToUpper(hex.EncodeToString(tmhash.Sum(txByte))), nil
 }
 
+func (base *baseClient) BuildUnsignedTx(msg []sdktypes.Msg, baseTx sdktypes.BaseTx) ([]byte, sdktypes.Error) {
+	builder, err := base.prepare(baseTx)
+	if err != nil {
+		return nil, sdktypes.Wrap(err)
+	}
+
+	txByte, err := builder.BuildUnsigned(msg)
+	if err != nil {
+		return nil, sdktypes.Wrap(err)
+	}
+	return txByte, nil
+}
+
 func (base *baseClient) BuildAndSign(msg []sdktypes.Msg, baseTx sdktypes.BaseTx) ([]byte, sdktypes.Error) {
 	builder, err := base.prepare(baseTx)
 	if err != nil {
@@ -302,9 +335,16 @@ func (base baseClient) Query(path string, data interface{}) ([]byte, error) {
 		}
 	}
 
+	if value, ok := base.queryCache.get(queryKindABCI, path, bz, base.height); ok {
+		if cached, ok := value.(cachedResponse); ok {
+			base.served.set(cached.height)
+			return cached.value, nil
+		}
+	}
+
 	opts := rpcclient.ABCIQueryOptions{
-		// Height: cliCtx.Height,
-		Prove: false,
+		Height: base.height,
+		Prove:  false,
 	}
 	result, err := base.ABCIQueryWithOptions(context.Background(), path, bz, opts)
 	if err != nil {
@@ -316,11 +356,27 @@ func (base baseClient) Query(path string, data interface{}) ([]byte, error) {
 		return nil, errors.New(resp.Log)
 	}
 
+	base.served.set(resp.Height)
+	base.queryCache.set(queryKindABCI, path, bz, base.height, resp.Height, cachedResponse{value: resp.Value, height: resp.Height})
 	return resp.Value, nil
 }
 
 func (base baseClient) QueryStore(key sdktypes.HexBytes, storeName string, height int64, prove bool) (res abci.ResponseQuery, err error) {
+	if height == 0 {
+		height = base.height
+	}
+
 	path := fmt.Sprintf("/store/%s/%s", storeName, "key")
+	// the proofs are not cached, they are verified against the header of the height
+	if !prove {
+		if value, ok := base.queryCache.get(queryKindStore, path, key, height); ok {
+			if res, ok := value.(abci.ResponseQuery); ok {
+				base.served.set(res.Height)
+				return res, nil
+			}
+		}
+	}
+
 	opts := rpcclient.ABCIQueryOptions{
 		Prove:  prove,
 		Height: height,
@@ -335,10 +391,59 @@ func (base baseClient) QueryStore(key sdktypes.HexBytes, storeName string, heigh
 	if !resp.IsOK() {
 		return res, errors.New(resp.Log)
 	}
+	base.served.set(resp.Height)
+	if !prove {
+		base.queryCache.set(queryKindStore, path, key, height, resp.Height, resp)
+	}
 	return resp, nil
 }
 
+// VerifiedQueryStore queries the key like QueryStore and verifies the proof of the value, or of its
+// absence when the value is empty, against the app hash of the header at height+1. A height of 0
+// queries the state committed by the latest header. The header is verified by the light client,
+// the query fails without one, see LightClientOption.
+func (base baseClient) VerifiedQueryStore(key sdktypes.HexBytes, storeName string, height int64) (abci.ResponseQuery, error) {
+	if base.light == nil {
+		return abci.ResponseQuery{}, errNoLightClient
+	}
+
+	if height == 0 {
+		height = base.height
+	}
+	if height == 0 {
+		latest, err := base.TrustedHeader(0)
+		if err != nil {
+			return abci.ResponseQuery{}, err
+		}
+		height = latest.Height - 1
+	}
+	if height <= 0 {
+		return abci.ResponseQuery{}, fmt.Errorf("no committed state to query yet")
+	}
+
+	res, err := base.QueryStore(key, storeName, height, true)
+	if err != nil {
+		return res, err
+	}
+	if res.Height != height {
+		return res, fmt.Errorf("queried the height %d, the node answered at %d", height, res.Height)
+	}
+
+	header, err := base.TrustedHeader(height + 1)
+	if err != nil {
+		return res, err
+	}
+	if err := store.VerifyStoreQuery(res.ProofOps, header.AppHash, storeName, key, res.Value); err != nil {
+		return res, fmt.Errorf("failed to verify the query of %s at the height %d: %s", storeName, height, err.Error())
+	}
+	return res, nil
+}
+
 func (base *baseClient) prepare(baseTx sdktypes.BaseTx) (*sdktypes.Factory, error) {
+	if base.height > 0 {
+		return nil, errPinnedTx
+	}
+
 	factory := sdktypes.NewFactory().
 		WithChainID(base.cfg.ChainID).
 		WithKeyManager(base.AccountQuery.Km).
@@ -412,6 +517,10 @@ func (base *baseClient) prepare(baseTx sdktypes.BaseTx) (*sdktypes.Factory, erro
 }
 
 func (base *baseClient) prepareWithAccount(addr string, accountNumber, sequence uint64, baseTx sdktypes.BaseTx) (*sdktypes.Factory, error) {
+	if base.height > 0 {
+		return nil, errPinnedTx
+	}
+
 	factory := sdktypes.NewFactory().
 		WithChainID(base.cfg.ChainID).
 		WithKeyManager(base.AccountQuery.Km).
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	cryptocodec "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestEd25519Keys(t *testing.T) {
+	km := NewKeyManager(store.NewMemory(nil), "ed25519")
+	address, _, err := km.Insert("ed", "12345678")
+	require.NoError(t, err)
+
+	pubKey, addr, err := km.Find("ed", "12345678")
+	require.NoError(t, err)
+	require.IsType(t, &ed25519.PubKey{}, pubKey)
+	require.Equal(t, address, addr.String())
+
+	signature, signer, err := km.Sign("ed", "12345678", []byte("data"))
+	require.NoError(t, err)
+	require.True(t, signer.Equals(pubKey))
+	require.True(t, pubKey.VerifySignature([]byte("data"), signature))
+
+	// the public key is packed in the signer infos of the txs
+	any, err := codectypes.NewAnyWithValue(pubKey.(*ed25519.PubKey))
+	require.NoError(t, err)
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	var unpacked tmcrypto.PubKey
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.True(t, pubKey.Equals(unpacked))
+
+	// the armor is imported as ed25519 whatever the algo of the KeyManager
+	armor, err := km.Export("ed", "12345678")
+	require.NoError(t, err)
+	secp256k1KM := NewKeyManager(store.NewMemory(nil), "secp256k1")
+	imported, err := secp256k1KM.Import("imported", "12345678", armor)
+	require.NoError(t, err)
+	require.Equal(t, address, imported)
+	pubKey, _, err = secp256k1KM.Find("imported", "12345678")
+	require.NoError(t, err)
+	require.IsType(t, &ed25519.PubKey{}, pubKey)
+
+	address, err = km.Recover("recovered", "12345678", testMnemonic, "")
+	require.NoError(t, err)
+	_, addr, err = km.Find("recovered", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+}
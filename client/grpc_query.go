@@ -0,0 +1,295 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"github.com/gogo/protobuf/proto"
+	gogodescriptor "github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCQuery invokes the gRPC query method, e.g. "/cosmos.bank.v1beta1.Query/Balance", with the
+// JSON of its request and returns the JSON of its response. The request and response types are
+// resolved from the gogoproto registry, or else from the reflection service of the node, so the
+// methods of the modules unknown to the SDK can be queried too.
+func (base *baseClient) GRPCQuery(ctx context.Context, method string, jsonRequest []byte) ([]byte, error) {
+	service, methodName, err := splitMethod(method)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(jsonRequest)) == 0 {
+		jsonRequest = []byte("{}")
+	}
+
+	conn, err := base.GenConn()
+	if err != nil {
+		return nil, err
+	}
+
+	if request, response, ok := registryMethod(service, methodName); ok {
+		if err := base.encodingConfig.Marshaler.UnmarshalJSON(jsonRequest, request); err != nil {
+			return nil, err
+		}
+		if err := conn.Invoke(ctx, method, request, response); err != nil {
+			return nil, err
+		}
+		return base.encodingConfig.Marshaler.MarshalJSON(response)
+	}
+
+	desc, err := base.reflection.method(ctx, conn, service, methodName)
+	if err != nil {
+		return nil, err
+	}
+	request := dynamicpb.NewMessage(desc.Input())
+	response := dynamicpb.NewMessage(desc.Output())
+	if err := (protojson.UnmarshalOptions{Resolver: desc.resolver}).Unmarshal(jsonRequest, request); err != nil {
+		return nil, err
+	}
+	if err := conn.Invoke(ctx, method, request, response); err != nil {
+		return nil, err
+	}
+	// the field names and defaults of the registry path
+	return protojson.MarshalOptions{Resolver: desc.resolver, UseProtoNames: true, EmitUnpopulated: true}.Marshal(response)
+}
+
+// splitMethod splits "/package.Service/Method" into the full name of the service and the method
+func splitMethod(method string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(method, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || !strings.Contains(parts[0], ".") {
+		return "", "", fmt.Errorf("invalid gRPC method %q, expected /package.Service/Method", method)
+	}
+	return parts[0], parts[1], nil
+}
+
+// registryMethod returns new request and response messages of the method when the file of its
+// service is registered, the file is looked up from the request type naming conventions
+func registryMethod(service, methodName string) (proto.Message, proto.Message, bool) {
+	pkg := service[:strings.LastIndex(service, ".")]
+
+	candidates := [][]byte{proto.FileDescriptor(strings.ReplaceAll(pkg, ".", "/") + "/query.proto")}
+	for _, name := range []string{pkg + ".Query" + methodName + "Request", pkg + "." + methodName + "Request"} {
+		typ := proto.MessageType(name)
+		if typ == nil {
+			continue
+		}
+		if msg, ok := reflect.New(typ.Elem()).Interface().(interface{ Descriptor() ([]byte, []int) }); ok {
+			gz, _ := msg.Descriptor()
+			candidates = append(candidates, gz)
+		}
+	}
+
+	for _, gz := range candidates {
+		fd, err := unzipFileDescriptor(gz)
+		if err != nil {
+			continue
+		}
+		for _, s := range fd.GetService() {
+			if fd.GetPackage()+"."+s.GetName() != service {
+				continue
+			}
+			for _, m := range s.GetMethod() {
+				if m.GetName() != methodName || m.GetClientStreaming() || m.GetServerStreaming() {
+					continue
+				}
+				request := newRegisteredMessage(m.GetInputType())
+				response := newRegisteredMessage(m.GetOutputType())
+				if request != nil && response != nil {
+					return request, response, true
+				}
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+func unzipFileDescriptor(gz []byte) (*gogodescriptor.FileDescriptorProto, error) {
+	if len(gz) == 0 {
+		return nil, fmt.Errorf("no file descriptor")
+	}
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	bz, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	fd := &gogodescriptor.FileDescriptorProto{}
+	if err := proto.Unmarshal(bz, fd); err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
+
+func newRegisteredMessage(typeName string) proto.Message {
+	typ := proto.MessageType(strings.TrimPrefix(typeName, "."))
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil
+	}
+	msg, _ := reflect.New(typ.Elem()).Interface().(proto.Message)
+	return msg
+}
+
+// reflectionMethods resolves the methods with the reflection service of the node, the resolved
+// methods are kept
+type reflectionMethods struct {
+	methods sync.Map
+}
+
+// reflectedMethod is a method resolved by the reflection service, with the files of its service
+type reflectedMethod struct {
+	protoreflect.MethodDescriptor
+	resolver filesResolver
+}
+
+func (r *reflectionMethods) method(ctx context.Context, conn grpc1.ClientConn, service, methodName string) (reflectedMethod, error) {
+	key := service + "/" + methodName
+	if r != nil {
+		if desc, ok := r.methods.Load(key); ok {
+			return desc.(reflectedMethod), nil
+		}
+	}
+
+	files, err := reflectServiceFiles(ctx, conn, service)
+	if err != nil {
+		return reflectedMethod{}, fmt.Errorf("resolve %s with the reflection service: %s", service, err.Error())
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return reflectedMethod{}, err
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return reflectedMethod{}, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(methodName))
+	if md == nil {
+		return reflectedMethod{}, fmt.Errorf("method %s not found in service %s", methodName, service)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return reflectedMethod{}, fmt.Errorf("streaming method %s is not supported", methodName)
+	}
+
+	desc := reflectedMethod{MethodDescriptor: md, resolver: filesResolver{files: files}}
+	if r != nil {
+		r.methods.Store(key, desc)
+	}
+	return desc, nil
+}
+
+// reflectServiceFiles fetches the file of the service and its imports from the reflection service
+func reflectServiceFiles(ctx context.Context, conn grpc1.ClientConn, service string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string]*descriptorpb.FileDescriptorProto)
+	receive := func(req *rpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if errRes := res.GetErrorResponse(); errRes != nil {
+			return fmt.Errorf("code: %d, message: %s", errRes.GetErrorCode(), errRes.GetErrorMessage())
+		}
+		for _, bz := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := protov2.Unmarshal(bz, fd); err != nil {
+				return err
+			}
+			fetched[fd.GetName()] = fd
+		}
+		return nil
+	}
+
+	if err := receive(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	}); err != nil {
+		return nil, err
+	}
+	// the imports not sent along, those the node does not serve are left unresolved
+	unserved := make(map[string]bool)
+	for missing := missingImports(fetched, unserved); len(missing) > 0; missing = missingImports(fetched, unserved) {
+		for _, name := range missing {
+			if err := receive(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			}); err != nil || fetched[name] == nil {
+				unserved[name] = true
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range fetched {
+		set.File = append(set.File, fd)
+	}
+	return protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(set)
+}
+
+func missingImports(fetched map[string]*descriptorpb.FileDescriptorProto, unserved map[string]bool) []string {
+	missing := make(map[string]bool)
+	for _, fd := range fetched {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := fetched[dep]; !ok && !unserved[dep] {
+				missing[dep] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	return names
+}
+
+// filesResolver resolves the types of the Any fields from the reflected files, then from the
+// types linked in the binary
+type filesResolver struct {
+	files *protoregistry.Files
+}
+
+func (r filesResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if r.files != nil {
+		if d, err := r.files.FindDescriptorByName(name); err == nil {
+			if md, ok := d.(protoreflect.MessageDescriptor); ok {
+				return dynamicpb.NewMessageType(md), nil
+			}
+		}
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(name)
+}
+
+func (r filesResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	return r.FindMessageByName(protoreflect.FullName(url[strings.LastIndex(url, "/")+1:]))
+}
+
+func (r filesResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByName(field)
+}
+
+func (r filesResolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	commoncodec "github.com/irisnet/core-sdk-go/common/codec"
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+)
+
+type balanceServer struct {
+	bank.UnimplementedQueryServer
+}
+
+func (*balanceServer) Balance(_ context.Context, req *bank.QueryBalanceRequest) (*bank.QueryBalanceResponse, error) {
+	coin := sdktypes.NewInt64Coin(req.Denom, int64(len(req.Address)))
+	return &bank.QueryBalanceResponse{Balance: &coin}, nil
+}
+
+// newQueryNode serves the bank queries, compiled in the SDK, and the health checks, only known by
+// the reflection service
+func newQueryNode(t *testing.T) *baseClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	bank.RegisterQueryServer(server, &balanceServer{})
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return &baseClient{
+		encodingConfig: sdktypes.EncodingConfig{Marshaler: commoncodec.NewProtoCodec(codectypes.NewInterfaceRegistry())},
+		reflection:     &reflectionMethods{},
+		AccountQuery:   AccountQuery{GRPCClient: NewGRPCClient(lis.Addr().String())},
+	}
+}
+
+func TestGRPCQuery(t *testing.T) {
+	base := newQueryNode(t)
+
+	// resolved from the registry
+	res, err := base.GRPCQuery(context.Background(), "/cosmos.bank.v1beta1.Query/Balance", []byte(`{"address":"iaa1","denom":"stake"}`))
+	require.NoError(t, err)
+	var balance struct {
+		Balance sdktypes.Coin `json:"balance"`
+	}
+	require.NoError(t, json.Unmarshal(res, &balance))
+	require.Equal(t, sdktypes.NewInt64Coin("stake", 4), balance.Balance)
+
+	// resolved by the reflection service
+	for i := 0; i < 2; i++ {
+		res, err = base.GRPCQuery(context.Background(), "/grpc.health.v1.Health/Check", nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"status":"SERVING"}`, string(res))
+	}
+	_, ok := base.reflection.methods.Load("grpc.health.v1.Health/Check")
+	require.True(t, ok)
+}
+
+func TestGRPCQueryErrors(t *testing.T) {
+	base := newQueryNode(t)
+
+	_, err := base.GRPCQuery(context.Background(), "Balance", nil)
+	require.Error(t, err)
+
+	_, err = base.GRPCQuery(context.Background(), "/grpc.health.v1.Health/Unknown", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	_, err = base.GRPCQuery(context.Background(), "/grpc.health.v1.Health/Watch", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "streaming")
+
+	_, err = base.GRPCQuery(context.Background(), "/unknown.v1.Query/Params", nil)
+	require.Error(t, err)
+
+	_, err = base.GRPCQuery(context.Background(), "/grpc.health.v1.Health/Check", []byte(`{"unknown":1}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/go-bip39"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+// hdAccountSeparator separates the wallet, the account and the index in the name of a derived account
+const hdAccountSeparator = "/"
+
+// CreateHDWallet stores the mnemonic, a new one is generated and returned when it is empty. The
+// account 0/0 is recorded and returned, the other accounts are signed with as "name/account/index".
+func (k KeyManager) CreateHDWallet(name, password, mnemonic string, coinType uint32) (string, string, error) {
+	if strings.Contains(name, hdAccountSeparator) {
+		return "", "", fmt.Errorf("wallet name %s must not contain %s", name, hdAccountSeparator)
+	}
+	if k.KeyDAO.Has(name) {
+		return "", "", fmt.Errorf("name %s has existed", name)
+	}
+
+	if len(mnemonic) == 0 {
+		entropy, err := bip39.NewEntropy(256)
+		if err != nil {
+			return "", "", err
+		}
+		if mnemonic, err = bip39.NewMnemonic(entropy); err != nil {
+			return "", "", err
+		}
+	}
+
+	secret := store.HDWalletSecret{Mnemonic: mnemonic, CoinType: coinType}
+	privKey, err := secret.Derive(k.Algo, 0, 0)
+	if err != nil {
+		return "", "", err
+	}
+	secret.AddAccounts(store.HDAccountPath{})
+
+	pubKey := privKey.PubKey()
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: secret.String(),
+		Algo:         k.Algo,
+	}
+	if err := k.KeyDAO.Write(name, password, info); err != nil {
+		return "", "", err
+	}
+	return types.AccAddress(pubKey.Address().Bytes()).String(), mnemonic, nil
+}
+
+// DeriveHDAccounts derives count accounts starting at fromIndex, they are not recorded in the wallet
+func (k KeyManager) DeriveHDAccounts(name, password string, account, fromIndex, count uint32) ([]types.HDAccount, error) {
+	info, secret, err := k.readHDWallet(name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	derive, err := secret.Deriver(info.Algo)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]types.HDAccount, 0, count)
+	for index := fromIndex; index-fromIndex < count; index++ {
+		hdAccount, err := deriveHDAccount(name, secret, derive, account, index)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, hdAccount)
+	}
+	return accounts, nil
+}
+
+// AddHDAccounts records the accounts in the wallet
+func (k KeyManager) AddHDAccounts(name, password string, accounts ...types.HDAccount) error {
+	info, secret, err := k.readHDWallet(name, password)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]store.HDAccountPath, len(accounts))
+	for i, a := range accounts {
+		paths[i] = store.HDAccountPath{Account: a.Account, Index: a.Index}
+	}
+	secret.AddAccounts(paths...)
+	info.PrivKeyArmor = secret.String()
+
+	return k.rewrite(name, password, info)
+}
+
+// HDAccounts returns the accounts recorded in the wallet
+func (k KeyManager) HDAccounts(name, password string) ([]types.HDAccount, error) {
+	info, secret, err := k.readHDWallet(name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	derive, err := secret.Deriver(info.Algo)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]types.HDAccount, len(secret.Accounts))
+	for i, p := range secret.Accounts {
+		if accounts[i], err = deriveHDAccount(name, secret, derive, p.Account, p.Index); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// readPrivKey returns the private key of a stored key, of a wallet (its account 0/0) or of an
+// account of a wallet named "wallet/account/index"
+func (k KeyManager) readPrivKey(name, password string) (tmcrypto.PrivKey, string, error) {
+	if wallet, account, index, ok := parseHDAccountName(name); ok && !k.KeyDAO.Has(name) {
+		info, secret, err := k.readHDWallet(wallet, password)
+		if err != nil {
+			return nil, "", err
+		}
+		privKey, err := secret.Derive(info.Algo, account, index)
+		return privKey, info.Algo, err
+	}
+
+	info, err := k.KeyDAO.Read(name, password)
+	if err != nil {
+		if _, ok := k.watchOnly(name); ok {
+			return nil, "", watchOnlyError{name}
+		}
+		return nil, "", err
+	}
+	if info.IsWatchOnly() {
+		return nil, "", watchOnlyError{name}
+	}
+	if secret, ok := store.ParseHDWalletSecret(info.PrivKeyArmor); ok {
+		privKey, err := secret.Derive(info.Algo, 0, 0)
+		return privKey, info.Algo, err
+	}
+
+	privKey, err := cryptoamino.PrivKeyFromBytes([]byte(info.PrivKeyArmor))
+	return privKey, info.Algo, err
+}
+
+func (k KeyManager) readHDWallet(name, password string) (store.KeyInfo, store.HDWalletSecret, error) {
+	info, err := k.KeyDAO.Read(name, password)
+	if err != nil {
+		return store.KeyInfo{}, store.HDWalletSecret{}, err
+	}
+	secret, ok := store.ParseHDWalletSecret(info.PrivKeyArmor)
+	if !ok {
+		return store.KeyInfo{}, store.HDWalletSecret{}, fmt.Errorf("%s is not an HD wallet", name)
+	}
+	return info, secret, nil
+}
+
+// rewrite replaces an entry in one step, so a crash never leaves the wallet without its entry
+func (k KeyManager) rewrite(name, password string, info store.KeyInfo) error {
+	replacer, ok := k.KeyDAO.(store.KeyReplacer)
+	if !ok {
+		return fmt.Errorf("the KeyDAO can not replace %s, it must implement store.KeyReplacer", name)
+	}
+	return replacer.Replace(name, password, info)
+}
+
+func deriveHDAccount(wallet string, secret store.HDWalletSecret, derive store.HDDeriver, account, index uint32) (types.HDAccount, error) {
+	privKey, err := derive(account, index)
+	if err != nil {
+		return types.HDAccount{}, err
+	}
+	return types.HDAccount{
+		Name:    hdAccountName(wallet, account, index),
+		Account: account,
+		Index:   index,
+		HDPath:  secret.HDPath(account, index),
+		Address: types.AccAddress(privKey.PubKey().Address().Bytes()).String(),
+	}, nil
+}
+
+func hdAccountName(wallet string, account, index uint32) string {
+	return strings.Join([]string{wallet, strconv.FormatUint(uint64(account), 10), strconv.FormatUint(uint64(index), 10)}, hdAccountSeparator)
+}
+
+func parseHDAccountName(name string) (wallet string, account, index uint32, ok bool) {
+	parts := strings.Split(name, hdAccountSeparator)
+	if len(parts) != 3 || len(parts[0]) == 0 {
+		return "", 0, 0, false
+	}
+	a, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	i, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	return parts[0], uint32(a), uint32(i), true
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/auth"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestHDWallet(t *testing.T) {
+	dao := store.NewMemory(store.AESGCM{ScryptLogN: 10})
+	km := NewKeyManager(dao, "secp256k1")
+
+	_, _, err := km.CreateHDWallet("a/b", "12345678", testMnemonic, 118)
+	require.Error(t, err)
+
+	address, mnemonic, err := km.CreateHDWallet("wallet", "12345678", testMnemonic, 118)
+	require.NoError(t, err)
+	require.Equal(t, testMnemonic, mnemonic)
+
+	_, _, err = km.CreateHDWallet("wallet", "12345678", testMnemonic, 118)
+	require.Error(t, err)
+	_, err = km.HDAccounts("wallet", "87654321")
+	require.Error(t, err)
+
+	accounts, err := km.DeriveHDAccounts("wallet", "12345678", 1, 3, 2)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, "wallet/1/4", accounts[1].Name)
+	require.Equal(t, hd.CreateHDPath(118, 1, 4).String(), accounts[1].HDPath)
+
+	// the derived accounts match the keys recovered with their path
+	recovered, err := km.Recover("recovered", "12345678", testMnemonic, accounts[1].HDPath)
+	require.NoError(t, err)
+	require.Equal(t, recovered, accounts[1].Address)
+
+	_, addr, err := km.Find("wallet", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+
+	pubKey, addr, err := km.Find("wallet/1/4", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, accounts[1].Address, addr.String())
+	signature, _, err := km.Sign("wallet/1/4", "12345678", []byte("data"))
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature([]byte("data"), signature))
+
+	_, _, err = km.Sign("wallet/1/4", "87654321", []byte("data"))
+	require.Error(t, err)
+
+	require.NoError(t, km.AddHDAccounts("wallet", "12345678", accounts...))
+	require.NoError(t, km.AddHDAccounts("wallet", "12345678", accounts[0]))
+	recorded, err := km.HDAccounts("wallet", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, []string{"wallet/0/0", "wallet/1/3", "wallet/1/4"}, hdAccountNames(recorded))
+
+	names, err := km.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"recovered", "wallet"}, names)
+}
+
+func TestDiscoverHDAccounts(t *testing.T) {
+	km := NewKeyManager(store.NewMemory(nil), "secp256k1")
+	_, _, err := km.CreateHDWallet("wallet", "12345678", testMnemonic, 118)
+	require.NoError(t, err)
+
+	accounts, err := km.DeriveHDAccounts("wallet", "12345678", 0, 0, 10)
+	require.NoError(t, err)
+
+	// the indices 0, 1 and 4 are used, the gap 2-3 is shorter than the limit
+	chain := accountQuery{KeyManager: km, count: new(int), batches: new(int), used: map[string]bool{
+		accounts[0].Address: true,
+		accounts[1].Address: true,
+		accounts[4].Address: true,
+	}}
+	client := keysClient{BIP44Params: *hd.NewFundraiserParams(0, 118, 0), KeyManager: chain}
+
+	discovered, sdkErr := client.DiscoverHDAccounts("wallet", "12345678", 0, 3)
+	require.NoError(t, sdkErr)
+	require.Equal(t, []string{"wallet/0/0", "wallet/0/1", "wallet/0/4"}, hdAccountNames(discovered))
+	require.Equal(t, 8, *chain.count, "scans until 3 consecutive unused accounts")
+	require.Equal(t, 3, *chain.batches, "derives by batches of the gap limit")
+
+	recorded, sdkErr := client.HDAccounts("wallet", "12345678")
+	require.NoError(t, sdkErr)
+	require.Equal(t, hdAccountNames(discovered), hdAccountNames(recorded))
+
+	discovered, sdkErr = client.DiscoverHDAccounts("wallet", "12345678", 0, 2)
+	require.NoError(t, sdkErr)
+	require.Equal(t, []string{"wallet/0/0", "wallet/0/1"}, hdAccountNames(discovered))
+
+	// only NotFound ends the scan, the other errors are returned
+	chain.unavailable = true
+	client.KeyManager = chain
+	_, sdkErr = client.DiscoverHDAccounts("wallet", "12345678", 0, 2)
+	require.Error(t, sdkErr)
+}
+
+// accountQuery serves the auth queries of the used accounts, it counts them and the derived batches
+type accountQuery struct {
+	KeyManager
+	grpc1.ClientConn
+	used        map[string]bool
+	unavailable bool
+	count       *int
+	batches     *int
+}
+
+func (q accountQuery) GenConn() (grpc1.ClientConn, error) {
+	return q, nil
+}
+
+func (q accountQuery) Invoke(_ context.Context, _ string, args, _ interface{}, _ ...grpc.CallOption) error {
+	*q.count++
+	address := args.(*auth.QueryAccountRequest).Address
+	switch {
+	case q.unavailable:
+		return status.Errorf(codes.Unavailable, "account %s not found on a syncing node", address)
+	case !q.used[address]:
+		return status.Errorf(codes.NotFound, "account %s not found", address)
+	}
+	return nil
+}
+
+func (q accountQuery) DeriveHDAccounts(name, password string, account, fromIndex, count uint32) ([]types.HDAccount, error) {
+	*q.batches++
+	return q.KeyManager.DeriveHDAccounts(name, password, account, fromIndex, count)
+}
+
+func hdAccountNames(accounts []types.HDAccount) []string {
+	names := make([]string, len(accounts))
+	for i, a := range accounts {
+		names[i] = a.Name
+	}
+	return names
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	commoncache "github.com/irisnet/core-sdk-go/common/cache"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+)
+
+// GRPCBlockHeightHeader is the gRPC metadata of the height a query is served at
+const GRPCBlockHeightHeader = sdktypes.GRPCBlockHeightHeader
+
+// errPinnedTx refuses the txs of a client pinned to a past height, whose accounts are historical
+var errPinnedTx = errors.New("can't build a tx with a client pinned to a past height, see WithHeight")
+
+// servedHeight records the height of the last response of a pinned client
+type servedHeight struct {
+	height int64
+}
+
+func (s *servedHeight) set(height int64) {
+	if s != nil && height > 0 {
+		atomic.StoreInt64(&s.height, height)
+	}
+}
+
+func (s *servedHeight) get() int64 {
+	if s == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&s.height)
+}
+
+// WithHeight returns a copy of the client whose gRPC and ABCI queries are served at the height, 0
+// for the latest one. The height each response was served at is returned by ServedHeight. The copy
+// caches the accounts apart, and refuses to build txs when pinned to a past height.
+func (base *baseClient) WithHeight(height int64) (sdktypes.BaseClient, error) {
+	if height < 0 {
+		return nil, fmt.Errorf("invalid height %d, must not be negative", height)
+	}
+
+	grpcClient := base.AccountQuery.GRPCClient
+	if hc, ok := grpcClient.(heightGRPCClient); ok {
+		grpcClient = hc.GRPCClient
+	}
+
+	pinned := *base
+	pinned.height = height
+	pinned.served = &servedHeight{}
+	pinned.AccountQuery.GRPCClient = heightGRPCClient{
+		GRPCClient: grpcClient,
+		height:     height,
+		served:     pinned.served,
+	}
+	pinned.AccountQuery.Queries = &pinned
+	pinned.AccountQuery.Cache = commoncache.NewCache(cacheCapacity, base.cfg.Cached)
+	return &pinned, nil
+}
+
+// ServedHeight returns the height the last query of a client of WithHeight was served at. It is
+// the pinned height for a past height, for 0 it is last-writer-wins: the queries of goroutines
+// sharing the client overwrite each other, so each goroutine pins its own client to read it.
+func (base *baseClient) ServedHeight() int64 {
+	return base.served.get()
+}
+
+// heightGRPCClient pins the connections of the GRPCClient
+type heightGRPCClient struct {
+	sdktypes.GRPCClient
+	height int64
+	served *servedHeight
+}
+
+func (c heightGRPCClient) GenConn() (grpc1.ClientConn, error) {
+	conn, err := c.GRPCClient.GenConn()
+	if err != nil {
+		return nil, err
+	}
+	return heightConn{ClientConn: conn, height: c.height, served: c.served}, nil
+}
+
+// heightConn sets the height of the requests and records the height of the responses
+type heightConn struct {
+	grpc1.ClientConn
+	height int64
+	served *servedHeight
+}
+
+func (c heightConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	opts = append(opts, grpc.Header(&header))
+	if err := c.ClientConn.Invoke(c.pin(ctx), method, args, reply, opts...); err != nil {
+		return err
+	}
+
+	if values := header.Get(GRPCBlockHeightHeader); len(values) > 0 {
+		if height, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+			c.served.set(height)
+		}
+	}
+	return nil
+}
+
+func (c heightConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.ClientConn.NewStream(c.pin(ctx), desc, method, opts...)
+}
+
+func (c heightConn) pin(ctx context.Context) context.Context {
+	if c.height <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, GRPCBlockHeightHeader, strconv.FormatInt(c.height, 10))
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	commoncache "github.com/irisnet/core-sdk-go/common/cache"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+)
+
+const latestHeight = 100
+
+// heightNode answers the queries at the requested height, the latest by default
+type heightNode struct {
+	sdktypes.TmClient
+	grpc1.ClientConn
+	requested string
+}
+
+func (n *heightNode) GenConn() (grpc1.ClientConn, error) {
+	return n, nil
+}
+
+func (n *heightNode) Invoke(ctx context.Context, _ string, _, _ interface{}, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	n.requested = strconv.Itoa(latestHeight)
+	if values := md.Get(GRPCBlockHeightHeader); len(values) > 0 {
+		n.requested = values[0]
+	}
+
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs(GRPCBlockHeightHeader, n.requested)
+		}
+	}
+	return nil
+}
+
+func (n *heightNode) ABCIQueryWithOptions(_ context.Context, _ string, _ tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	height := opts.Height
+	if height == 0 {
+		height = latestHeight
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Height: height}}, nil
+}
+
+func TestWithHeight(t *testing.T) {
+	node := &heightNode{}
+	base := &baseClient{
+		TmClient:     node,
+		cfg:          &sdktypes.ClientConfig{Cached: true},
+		AccountQuery: AccountQuery{GRPCClient: node, Logger: log.NewNopLogger(), Cache: commoncache.NewCache(cacheCapacity, true)},
+	}
+
+	pinned, err := base.WithHeight(42)
+	require.NoError(t, err)
+	conn, err := pinned.GenConn()
+	require.NoError(t, err)
+	require.NoError(t, conn.Invoke(context.Background(), "/cosmos.bank.v1beta1.Query/TotalSupply", nil, nil))
+	require.Equal(t, "42", node.requested)
+	require.Equal(t, int64(42), pinned.ServedHeight())
+
+	res, err := pinned.QueryStore([]byte("key"), "bank", 0, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), res.Height)
+
+	// the pinned client caches the accounts apart and builds no tx
+	pinnedBase := pinned.(*baseClient)
+	pinnedBase.saveAccount(sdktypes.BaseAccount{Address: "addr", AccountNumber: 1, Sequence: 3})
+	_, err = base.Get(base.prefixKey("addr"))
+	require.Error(t, err)
+	_, err = pinnedBase.prepare(sdktypes.BaseTx{})
+	require.Equal(t, errPinnedTx, err)
+	_, err = pinnedBase.prepareWithAccount("addr", 1, 3, sdktypes.BaseTx{})
+	require.Equal(t, errPinnedTx, err)
+
+	// pinning again replaces the height
+	repinned, err := pinned.WithHeight(7)
+	require.NoError(t, err)
+	conn, err = repinned.GenConn()
+	require.NoError(t, err)
+	require.NoError(t, conn.Invoke(context.Background(), "/cosmos.bank.v1beta1.Query/TotalSupply", nil, nil))
+	require.Equal(t, "7", node.requested)
+
+	// a client pinned to 0 records the latest height
+	latest, err := base.WithHeight(0)
+	require.NoError(t, err)
+	_, err = latest.Query("/custom/bank/total", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(latestHeight), latest.ServedHeight())
+
+	// the unpinned client is unchanged
+	conn, err = base.GenConn()
+	require.NoError(t, err)
+	require.NoError(t, conn.Invoke(context.Background(), "/cosmos.bank.v1beta1.Query/TotalSupply", nil, nil))
+	require.Equal(t, "100", node.requested)
+	require.Equal(t, int64(0), base.ServedHeight())
+
+	_, err = base.WithHeight(-1)
+	require.Error(t, err)
+}
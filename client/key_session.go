@@ -0,0 +1,165 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
+	"github.com/irisnet/core-sdk-go/types"
+)
+
+// keySession holds a decrypted private key until it expires or is locked
+type keySession struct {
+	// mtx prevents the key from being zeroed while signing
+	mtx     sync.RWMutex
+	privKey tmcrypto.PrivKey
+	pubKey  tmcrypto.PubKey
+	address types.AccAddress
+	// salt and verifier check the password of the callers without decrypting the key again
+	salt     []byte
+	verifier []byte
+	timer    *time.Timer
+}
+
+// keySessions are shared by all the copies of a KeyManager
+type keySessions struct {
+	mtx      sync.Mutex
+	sessions map[string]*keySession
+}
+
+func newKeySessions() *keySessions {
+	return &keySessions{sessions: make(map[string]*keySession)}
+}
+
+// Unlock decrypts the key once and keeps it in memory for ttl. Until then Sign and Find
+// with the same password skip the KeyDAO.
+func (k KeyManager) Unlock(name, password string, ttl time.Duration) error {
+	if k.sessions == nil {
+		return fmt.Errorf("key sessions are not enabled, create the KeyManager with NewKeyManager")
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+
+	privKey, algo, err := k.readPrivKey(name, password)
+	if isWatchOnlyError(err) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("name %s not exist", name)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+
+	pubKey := privKey.PubKey()
+	session := &keySession{
+		privKey:  privKey,
+		pubKey:   FromTmPubKey(algo, pubKey),
+		address:  types.AccAddress(pubKey.Address().Bytes()),
+		salt:     salt,
+		verifier: sessionVerifier(salt, password),
+	}
+
+	k.sessions.mtx.Lock()
+	defer k.sessions.mtx.Unlock()
+	k.sessions.lockLocked(name)
+	session.timer = time.AfterFunc(ttl, func() {
+		k.sessions.mtx.Lock()
+		defer k.sessions.mtx.Unlock()
+		// the key may have been unlocked again in the meantime
+		if k.sessions.sessions[name] == session {
+			k.sessions.lockLocked(name)
+		}
+	})
+	k.sessions.sessions[name] = session
+	return nil
+}
+
+// Lock zeroes the unlocked key immediately
+func (k KeyManager) Lock(name string) {
+	if k.sessions == nil {
+		return
+	}
+	k.sessions.mtx.Lock()
+	defer k.sessions.mtx.Unlock()
+	k.sessions.lockLocked(name)
+}
+
+// session returns the unlocked key when the password matches the one used to unlock it
+func (k KeyManager) session(name, password string) (*keySession, bool) {
+	if k.sessions == nil {
+		return nil, false
+	}
+	k.sessions.mtx.Lock()
+	defer k.sessions.mtx.Unlock()
+
+	session, ok := k.sessions.sessions[name]
+	if !ok || subtle.ConstantTimeCompare(sessionVerifier(session.salt, password), session.verifier) != 1 {
+		return nil, false
+	}
+	return session, true
+}
+
+func (s *keySessions) lockLocked(name string) {
+	session, ok := s.sessions[name]
+	if !ok {
+		return
+	}
+	session.timer.Stop()
+	delete(s.sessions, name)
+
+	session.mtx.Lock()
+	defer session.mtx.Unlock()
+	zeroPrivKey(session.privKey)
+	session.privKey = nil
+}
+
+// sign returns false when the session was locked in the meantime
+func (s *keySession) sign(data []byte) (signature []byte, ok bool, err error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.privKey == nil {
+		return nil, false, nil
+	}
+	signature, err = s.privKey.Sign(data)
+	return signature, true, err
+}
+
+func sessionVerifier(salt []byte, password string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(password))
+	return h.Sum(nil)
+}
+
+// zeroPrivKey overwrites the key material. Go offers no guarantee that no copy is left
+// elsewhere in memory, so this only narrows the exposure.
+func zeroPrivKey(privKey tmcrypto.PrivKey) {
+	var key []byte
+	switch privKey := privKey.(type) {
+	case *secp256k1.PrivKey:
+		key = privKey.Key
+	case *sm2.PrivKey:
+		key = privKey.Key
+	case *ethsecp256k1.PrivKey:
+		key = privKey.Key
+	case *ed25519.PrivKey:
+		key = privKey.Key
+	}
+	for i := range key {
+		key[i] = 0
+	}
+}
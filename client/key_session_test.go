@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestKeySession(t *testing.T) {
+	dao := store.NewMemory(nil)
+	km := NewKeyManager(dao, "secp256k1")
+	address, _, err := km.Insert("bot", "12345678")
+	require.NoError(t, err)
+
+	require.Error(t, km.Unlock("bot", "87654321", time.Minute))
+	require.NoError(t, km.Unlock("bot", "12345678", time.Minute))
+
+	// the key is served from the session, not from the store
+	require.NoError(t, dao.Delete("bot", "12345678"))
+
+	pubKey, addr, err := km.Find("bot", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+
+	signature, _, err := km.Sign("bot", "12345678", []byte("data"))
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature([]byte("data"), signature))
+
+	// the session does not bypass the password
+	_, _, err = km.Sign("bot", "87654321", []byte("data"))
+	require.Error(t, err)
+
+	km.Lock("bot")
+	_, _, err = km.Find("bot", "12345678")
+	require.Error(t, err)
+
+	_, _, err = km.Insert("bot", "12345678")
+	require.NoError(t, err)
+	require.NoError(t, km.Unlock("bot", "12345678", 10*time.Millisecond))
+	session, ok := km.session("bot", "12345678")
+	require.True(t, ok)
+	require.Eventually(t, func() bool {
+		_, ok := km.session("bot", "12345678")
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+
+	session.mtx.RLock()
+	defer session.mtx.RUnlock()
+	require.Nil(t, session.privKey)
+
+	require.Error(t, KeyManager{KeyDAO: dao}.Unlock("bot", "12345678", time.Minute))
+}
@@ -1,24 +1,54 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"time"
+
 	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
 
 	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	kmg "github.com/irisnet/core-sdk-go/common/crypto"
 	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
 	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
 	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
 	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
 	commoncryptotypes "github.com/irisnet/core-sdk-go/common/crypto/types"
 	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/auth"
 	"github.com/irisnet/core-sdk-go/types/store"
 )
 
+var (
+	_ types.KeyManager    = KeyManager{}
+	_ types.KeyLister     = KeyManager{}
+	_ types.KeyEditor     = KeyManager{}
+	_ types.KeySessions   = KeyManager{}
+	_ types.HDWallets     = KeyManager{}
+	_ types.KeyFormats    = KeyManager{}
+	_ types.MnemonicKeys  = KeyManager{}
+	_ types.WatchOnlyKeys = KeyManager{}
+	_ types.KeyShares     = KeyManager{}
+)
+
 type KeyManager struct {
 	KeyDAO store.KeyDAO
 	Algo   string
+
+	sessions *keySessions
+}
+
+// NewKeyManager returns a KeyManager supporting Unlock
+func NewKeyManager(keyDAO store.KeyDAO, algo string) KeyManager {
+	return KeyManager{
+		KeyDAO:   keyDAO,
+		Algo:     algo,
+		sessions: newKeySessions(),
+	}
 }
 
 func (k KeyManager) Add(name, password string) (string, string, types.Error) {
@@ -27,22 +57,26 @@ func (k KeyManager) Add(name, password string) (string, string, types.Error) {
 }
 
 func (k KeyManager) Sign(name, password string, data []byte) ([]byte, tmcrypto.PubKey, error) {
-	info, err := k.KeyDAO.Read(name, password)
-	if err != nil {
-		return nil, nil, fmt.Errorf("name %s not exist", name)
+	if session, ok := k.session(name, password); ok {
+		if signature, ok, err := session.sign(data); ok {
+			return signature, session.pubKey, err
+		}
 	}
 
-	km, err := kmg.NewPrivateKeyManager([]byte(info.PrivKeyArmor), string(info.Algo))
+	privKey, algo, err := k.readPrivKey(name, password)
+	if isWatchOnlyError(err) {
+		return nil, nil, err
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("name %s not exist", name)
 	}
 
-	signByte, err := km.Sign(data)
+	signByte, err := privKey.Sign(data)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return signByte, FromTmPubKey(info.Algo, km.ExportPubKey()), nil
+	return signByte, FromTmPubKey(algo, privKey.PubKey()), nil
 }
 
 func (k KeyManager) Insert(name, password string) (string, string, error) {
@@ -110,6 +144,77 @@ func (k KeyManager) Recover(name, password, mnemonic, hdPath string) (string, er
 	return address, nil
 }
 
+func (k KeyManager) InsertWithOptions(name, password string, opts types.MnemonicOptions) (string, string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", "", fmt.Errorf("name %s has existed", name)
+	}
+
+	kmgOpts, err := mnemonicOptions(opts)
+	if err != nil {
+		return "", "", err
+	}
+	km, err := kmg.NewAlgoKeyManagerWithOptions(k.Algo, kmgOpts)
+	if err != nil {
+		return "", "", err
+	}
+
+	mnemonic, priv := km.Generate()
+	address, err := k.write(name, password, priv)
+	if err != nil {
+		return "", "", err
+	}
+	return address, mnemonic, nil
+}
+
+func (k KeyManager) RecoverWithOptions(name, password, mnemonic string, opts types.MnemonicOptions) (string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", fmt.Errorf("name %s has existed", name)
+	}
+
+	kmgOpts, err := mnemonicOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	if len(opts.Language) == 0 {
+		// detect the language of the mnemonic
+		kmgOpts.Wordlist = nil
+	}
+	km, err := kmg.NewMnemonicKeyManagerWithOptions(mnemonic, k.Algo, kmgOpts)
+	if err != nil {
+		return "", err
+	}
+
+	_, priv := km.Generate()
+	return k.write(name, password, priv)
+}
+
+func (k KeyManager) write(name, password string, priv tmcrypto.PrivKey) (string, error) {
+	pubKey := priv.PubKey()
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: string(cryptoamino.MarshalPrivKey(priv)),
+		Algo:         k.Algo,
+	}
+	if err := k.KeyDAO.Write(name, password, info); err != nil {
+		return "", err
+	}
+	return types.AccAddress(pubKey.Address().Bytes()).String(), nil
+}
+
+func mnemonicOptions(opts types.MnemonicOptions) (kmg.MnemonicOptions, error) {
+	wordlist, err := hd.WordlistByLanguage(opts.Language)
+	if err != nil {
+		return kmg.MnemonicOptions{}, err
+	}
+	return kmg.MnemonicOptions{
+		BIP39Passphrase: opts.BIP39Passphrase,
+		WordCount:       opts.WordCount,
+		Wordlist:        wordlist,
+		HDPath:          opts.HDPath,
+	}, nil
+}
+
 func (k KeyManager) Import(name, password, armor string) (string, error) {
 	if k.KeyDAO.Has(name) {
 		return "", fmt.Errorf("%s has existed", name)
@@ -121,6 +226,11 @@ func (k KeyManager) Import(name, password, armor string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// the armors of older versions carry no algo, it is given by the type of the key
+	algo, err := pubKeyAlgo(priv.PubKey())
+	if err != nil {
+		algo = k.Algo
+	}
 
 	pubKey := km.ExportPubKey()
 	address := types.AccAddress(pubKey.Address().Bytes()).String()
@@ -129,7 +239,7 @@ func (k KeyManager) Import(name, password, armor string) (string, error) {
 		Name:         name,
 		PubKey:       cryptoamino.MarshalPubkey(pubKey),
 		PrivKeyArmor: string(cryptoamino.MarshalPrivKey(priv)),
-		Algo:         k.Algo,
+		Algo:         algo,
 	}
 
 	err = k.KeyDAO.Write(name, password, info)
@@ -140,28 +250,138 @@ func (k KeyManager) Import(name, password, armor string) (string, error) {
 }
 
 func (k KeyManager) Export(name, password string) (armor string, err error) {
-	info, err := k.KeyDAO.Read(name, password)
+	privKey, algo, err := k.readPrivKey(name, password)
+	if isWatchOnlyError(err) {
+		return armor, err
+	}
 	if err != nil {
 		return armor, fmt.Errorf("name %s not exist", name)
 	}
 
-	km, err := kmg.NewPrivateKeyManager([]byte(info.PrivKeyArmor), info.Algo)
+	return kmg.EncryptArmorPrivKey(privKey, password, algo), nil
+}
+
+// ImportKeystore imports a key exported by geth or MetaMask, the keystore is decrypted with the password
+func (k KeyManager) ImportKeystore(name, password, keystore string) (string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", fmt.Errorf("%s has existed", name)
+	}
+
+	priv, err := kmg.DecryptKeystore(keystore, password)
 	if err != nil {
 		return "", err
 	}
 
-	return km.ExportPrivKey(password)
+	pubKey := priv.PubKey()
+	address := types.AccAddress(pubKey.Address().Bytes()).String()
+
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: string(cryptoamino.MarshalPrivKey(priv)),
+		Algo:         ethsecp256k1.KeyType,
+	}
+
+	if err = k.KeyDAO.Write(name, password, info); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// ExportKeystore exports an eth_secp256k1 key as a keystore encrypted with the password
+func (k KeyManager) ExportKeystore(name, password, kdf string) (string, error) {
+	privKey, _, err := k.readPrivKey(name, password)
+	if isWatchOnlyError(err) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the key %s: %w", name, err)
+	}
+
+	return kmg.EncryptKeystore(privKey, password, kdf)
+}
+
+// ImportPKCS8 imports an SM2 key exported by GM/T tooling
+func (k KeyManager) ImportPKCS8(name, password, pem string) (string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", fmt.Errorf("%s has existed", name)
+	}
+
+	priv, err := sm2.ParsePKCS8PrivateKey([]byte(pem), []byte(password))
+	if err != nil {
+		return "", err
+	}
+
+	pubKey := priv.PubKey()
+	address := types.AccAddress(pubKey.Address().Bytes()).String()
+
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: string(cryptoamino.MarshalPrivKey(&priv)),
+		Algo:         "sm2",
+	}
+
+	if err = k.KeyDAO.Write(name, password, info); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// ExportPKCS8 exports an SM2 key as a PKCS#8 PEM encrypted with the password
+func (k KeyManager) ExportPKCS8(name, password string) (string, error) {
+	privKey, _, err := k.readPrivKey(name, password)
+	if isWatchOnlyError(err) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the key %s: %w", name, err)
+	}
+
+	sm2PrivKey, ok := privKey.(*sm2.PrivKey)
+	if !ok {
+		return "", fmt.Errorf("only sm2 keys can be exported as PKCS#8")
+	}
+	bz, err := sm2.MarshalPKCS8PrivateKey(*sm2PrivKey, []byte(password))
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
 }
 
 func (k KeyManager) Delete(name, password string) error {
-	return k.KeyDAO.Delete(name, password)
+	if err := k.KeyDAO.Delete(name, password); err != nil {
+		return err
+	}
+	k.Lock(name)
+	return nil
 }
 
 func (k KeyManager) Find(name, password string) (tmcrypto.PubKey, types.AccAddress, error) {
+	if session, ok := k.session(name, password); ok {
+		return session.pubKey, session.address, nil
+	}
+
+	if _, _, _, ok := parseHDAccountName(name); ok && !k.KeyDAO.Has(name) {
+		privKey, algo, err := k.readPrivKey(name, password)
+		if err != nil {
+			return nil, nil, types.WrapWithMessage(err, "name %s not exist", name)
+		}
+		pubKey := privKey.PubKey()
+		return FromTmPubKey(algo, pubKey), types.AccAddress(pubKey.Address().Bytes()), nil
+	}
+
 	info, err := k.KeyDAO.Read(name, password)
 	if err != nil {
+		// the public key of a watch-only key is readable without its password
+		if m, ok := k.watchOnly(name); ok {
+			return findWatchOnly(m.Algo, m.PubKey, m.Address)
+		}
 		return nil, nil, types.WrapWithMessage(err, "name %s not exist", name)
 	}
+	if info.IsWatchOnly() {
+		return findWatchOnly(info.Algo, info.PubKey, info.Address)
+	}
 
 	pubKey, err := cryptoamino.PubKeyFromBytes(info.PubKey)
 	if err != nil {
@@ -171,6 +391,69 @@ func (k KeyManager) Find(name, password string) (tmcrypto.PubKey, types.AccAddre
 	return FromTmPubKey(info.Algo, pubKey), types.AccAddress(pubKey.Address().Bytes()), nil
 }
 
+func (k KeyManager) List() ([]string, error) {
+	return k.KeyDAO.List()
+}
+
+func (k KeyManager) ListMetadata() ([]types.KeyMetadata, error) {
+	metadata, err := k.KeyDAO.ListMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.KeyMetadata, len(metadata))
+	for i, m := range metadata {
+		if res[i], err = ParseKeyMetadata(m); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ParseKeyMetadata converts the metadata of a KeyDAO into its displayed form
+func ParseKeyMetadata(m store.KeyMetadata) (types.KeyMetadata, error) {
+	res := types.KeyMetadata{
+		Name:      m.Name,
+		Algo:      m.Algo,
+		CreatedAt: m.CreatedAt,
+		WatchOnly: m.Type == store.TypeOffline,
+	}
+	if len(m.PubKey) == 0 {
+		if len(m.Address) > 0 {
+			res.Address = types.AccAddress(m.Address).String()
+		}
+		return res, nil
+	}
+
+	pubKey, err := cryptoamino.PubKeyFromBytes(m.PubKey)
+	if err != nil {
+		return res, types.WrapWithMessage(err, "invalid pubkey of %s", m.Name)
+	}
+	address := types.AccAddress(pubKey.Address().Bytes())
+	res.PubKey = FromTmPubKey(m.Algo, pubKey)
+	res.Address = address.String()
+	if m.Algo == ethsecp256k1.KeyType {
+		res.EthAddress = address.EthAddress()
+	}
+	return res, nil
+}
+
+func (k KeyManager) Rename(oldName, newName, password string) error {
+	if err := k.KeyDAO.Rename(oldName, newName, password); err != nil {
+		return err
+	}
+	k.Lock(oldName)
+	return nil
+}
+
+func (k KeyManager) ChangePassword(name, oldPassword, newPassword string) error {
+	if err := k.KeyDAO.ChangePassword(name, oldPassword, newPassword); err != nil {
+		return err
+	}
+	k.Lock(name)
+	return nil
+}
+
 func FromTmPubKey(Algo string, pubKey tmcrypto.PubKey) commoncryptotypes.PubKey {
 	var pubkey commoncryptotypes.PubKey
 	pubkeyBytes := pubKey.Bytes()
@@ -181,6 +464,8 @@ func FromTmPubKey(Algo string, pubKey tmcrypto.PubKey) commoncryptotypes.PubKey
 		pubkey = &secp256k1.PubKey{Key: pubkeyBytes}
 	case ethsecp256k1.KeyType:
 		pubkey = &ethsecp256k1.PubKey{Key: pubkeyBytes}
+	case string(hd.Ed25519Type):
+		pubkey = &ed25519.PubKey{Key: pubkeyBytes}
 	}
 	return pubkey
 }
@@ -193,6 +478,37 @@ type Client interface {
 	Export(name, password string) (privKeyArmor string, err types.Error)
 	Delete(name, password string) types.Error
 	Show(name, password string) (string, types.Error)
+	List() ([]string, types.Error)
+	ListMetadata() ([]types.KeyMetadata, types.Error)
+	Rename(oldName, newName, password string) types.Error
+	ChangePassword(name, oldPassword, newPassword string) types.Error
+	Unlock(name, password string, ttl time.Duration) types.Error
+	Lock(name string)
+	CreateHDWallet(name, password, mnemonic string) (address string, mnemonicOut string, err types.Error)
+	DeriveHDAccount(name, password string, account, index uint32) (types.HDAccount, types.Error)
+	HDAccounts(name, password string) ([]types.HDAccount, types.Error)
+	DiscoverHDAccounts(name, password string, account uint32, gapLimit int) ([]types.HDAccount, types.Error)
+	ImportKeystore(name, password, keystore string) (address string, err types.Error)
+	ExportKeystore(name, password, kdf string) (keystore string, err types.Error)
+	// ShowEthAddress returns the bech32 and the 0x hex addresses of a key
+	ShowEthAddress(name, password string) (address, ethAddress string, err types.Error)
+	ImportPKCS8(name, password, pem string) (address string, err types.Error)
+	ExportPKCS8(name, password string) (pem string, err types.Error)
+	// ExportPublicKey returns the SubjectPublicKeyInfo PEM of an SM2 key
+	ExportPublicKey(name, password string) (pem string, err types.Error)
+	// ImportPublicKey stores the SubjectPublicKeyInfo PEM of an SM2 key as a watch-only key
+	ImportPublicKey(name, password, pem string) (address string, err types.Error)
+	AddWithOptions(name, password string, opts types.MnemonicOptions) (address string, mnemonic string, err types.Error)
+	RecoverWithOptions(name, password, mnemonic string, opts types.MnemonicOptions) (address string, err types.Error)
+	// SignArbitrary and VerifyArbitrary sign and verify off-chain data with the ADR-036 sign doc
+	SignArbitrary(name, password string, data []byte) (types.ArbitrarySignature, types.Error)
+	VerifyArbitrary(address string, pubKey tmcrypto.PubKey, data, signature []byte) types.Error
+	// ImportPubKey and ImportAddress store watch-only keys, their txs are built with BuildUnsignedTx
+	ImportPubKey(name, password string, pubKey tmcrypto.PubKey) (address string, err types.Error)
+	ImportAddress(name, password, address string) types.Error
+	// ExportShares splits a key into count armored shares, any threshold of them restore it with ImportShares
+	ExportShares(name, password string, threshold, count int) (shares []string, err types.Error)
+	ImportShares(name, password string, shares []string) (address string, err types.Error)
 }
 
 type keysClient struct {
@@ -208,6 +524,78 @@ func NewKeysClient(cfg types.ClientConfig, keyManager types.KeyManager) Client {
 	return keysClient{*BIP44Params, keyManager}
 }
 
+// keys returns what holds the keys, the optional features of a key manager are asserted on it
+func (k keysClient) keys() interface{} {
+	keyManager := k.KeyManager
+	if base, ok := keyManager.(*baseClient); ok {
+		keyManager = base.KeyManager
+	}
+	if signer, ok := keyManager.(signerKeyManager); ok {
+		return signer.Signer
+	}
+	return keyManager
+}
+
+func errKeysUnsupported(feature string) types.Error {
+	return types.Wrapf("the key manager does not support %s", feature)
+}
+
+func (k keysClient) lister() (types.KeyLister, types.Error) {
+	if lister, ok := k.keys().(types.KeyLister); ok {
+		return lister, nil
+	}
+	return nil, errKeysUnsupported("listing the keys")
+}
+
+func (k keysClient) editor() (types.KeyEditor, types.Error) {
+	if editor, ok := k.keys().(types.KeyEditor); ok {
+		return editor, nil
+	}
+	return nil, errKeysUnsupported("editing the keys")
+}
+
+func (k keysClient) sessions() (types.KeySessions, types.Error) {
+	if sessions, ok := k.keys().(types.KeySessions); ok {
+		return sessions, nil
+	}
+	return nil, errKeysUnsupported("unlocking the keys")
+}
+
+func (k keysClient) wallets() (types.HDWallets, types.Error) {
+	if wallets, ok := k.keys().(types.HDWallets); ok {
+		return wallets, nil
+	}
+	return nil, errKeysUnsupported("HD wallets")
+}
+
+func (k keysClient) formats() (types.KeyFormats, types.Error) {
+	if formats, ok := k.keys().(types.KeyFormats); ok {
+		return formats, nil
+	}
+	return nil, errKeysUnsupported("keystores and PKCS#8")
+}
+
+func (k keysClient) mnemonics() (types.MnemonicKeys, types.Error) {
+	if mnemonics, ok := k.keys().(types.MnemonicKeys); ok {
+		return mnemonics, nil
+	}
+	return nil, errKeysUnsupported("mnemonic options")
+}
+
+func (k keysClient) watchOnly() (types.WatchOnlyKeys, types.Error) {
+	if watchOnly, ok := k.keys().(types.WatchOnlyKeys); ok {
+		return watchOnly, nil
+	}
+	return nil, errKeysUnsupported("watch-only keys")
+}
+
+func (k keysClient) shares() (types.KeyShares, types.Error) {
+	if shares, ok := k.keys().(types.KeyShares); ok {
+		return shares, nil
+	}
+	return nil, errKeysUnsupported("key shares")
+}
+
 func (k keysClient) Add(name, password string) (string, string, types.Error) {
 	address, mnemonic, err := k.Insert(name, password)
 	return address, mnemonic, types.Wrap(err)
@@ -223,6 +611,24 @@ func (k keysClient) RecoverWithHDPath(name, password, mnemonic, hdPath string) (
 	return address, types.Wrap(err)
 }
 
+func (k keysClient) AddWithOptions(name, password string, opts types.MnemonicOptions) (string, string, types.Error) {
+	mnemonics, sdkErr := k.mnemonics()
+	if sdkErr != nil {
+		return "", "", sdkErr
+	}
+	address, mnemonic, err := mnemonics.InsertWithOptions(name, password, opts)
+	return address, mnemonic, types.Wrap(err)
+}
+
+func (k keysClient) RecoverWithOptions(name, password, mnemonic string, opts types.MnemonicOptions) (string, types.Error) {
+	mnemonics, sdkErr := k.mnemonics()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := mnemonics.RecoverWithOptions(name, password, mnemonic, opts)
+	return address, types.Wrap(err)
+}
+
 func (k keysClient) Import(name, password, privKeyArmor string) (string, types.Error) {
 	address, err := k.KeyManager.Import(name, password, privKeyArmor)
 	return address, types.Wrap(err)
@@ -245,3 +651,263 @@ func (k keysClient) Show(name, password string) (string, types.Error) {
 	}
 	return address.String(), nil
 }
+
+func (k keysClient) List() ([]string, types.Error) {
+	lister, sdkErr := k.lister()
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	names, err := lister.List()
+	return names, types.Wrap(err)
+}
+
+func (k keysClient) ListMetadata() ([]types.KeyMetadata, types.Error) {
+	lister, sdkErr := k.lister()
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	metadata, err := lister.ListMetadata()
+	return metadata, types.Wrap(err)
+}
+
+func (k keysClient) Rename(oldName, newName, password string) types.Error {
+	editor, sdkErr := k.editor()
+	if sdkErr != nil {
+		return sdkErr
+	}
+	return types.Wrap(editor.Rename(oldName, newName, password))
+}
+
+func (k keysClient) ChangePassword(name, oldPassword, newPassword string) types.Error {
+	editor, sdkErr := k.editor()
+	if sdkErr != nil {
+		return sdkErr
+	}
+	return types.Wrap(editor.ChangePassword(name, oldPassword, newPassword))
+}
+
+func (k keysClient) ImportKeystore(name, password, keystore string) (string, types.Error) {
+	formats, sdkErr := k.formats()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := formats.ImportKeystore(name, password, keystore)
+	return address, types.Wrap(err)
+}
+
+func (k keysClient) ExportKeystore(name, password, kdf string) (string, types.Error) {
+	formats, sdkErr := k.formats()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	keystore, err := formats.ExportKeystore(name, password, kdf)
+	return keystore, types.Wrap(err)
+}
+
+func (k keysClient) ShowEthAddress(name, password string) (string, string, types.Error) {
+	_, address, err := k.KeyManager.Find(name, password)
+	if err != nil {
+		return "", "", types.Wrap(err)
+	}
+	return address.String(), address.EthAddress(), nil
+}
+
+func (k keysClient) ImportPubKey(name, password string, pubKey tmcrypto.PubKey) (string, types.Error) {
+	watchOnly, sdkErr := k.watchOnly()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := watchOnly.ImportPubKey(name, password, pubKey)
+	return address, types.Wrap(err)
+}
+
+func (k keysClient) ImportAddress(name, password, address string) types.Error {
+	watchOnly, sdkErr := k.watchOnly()
+	if sdkErr != nil {
+		return sdkErr
+	}
+	return types.Wrap(watchOnly.ImportAddress(name, password, address))
+}
+
+func (k keysClient) ExportShares(name, password string, threshold, count int) ([]string, types.Error) {
+	keyShares, sdkErr := k.shares()
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	shares, err := keyShares.ExportShares(name, password, threshold, count)
+	return shares, types.Wrap(err)
+}
+
+func (k keysClient) ImportShares(name, password string, shares []string) (string, types.Error) {
+	keyShares, sdkErr := k.shares()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := keyShares.ImportShares(name, password, shares)
+	return address, types.Wrap(err)
+}
+
+func (k keysClient) ImportPKCS8(name, password, pem string) (string, types.Error) {
+	formats, sdkErr := k.formats()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := formats.ImportPKCS8(name, password, pem)
+	return address, types.Wrap(err)
+}
+
+func (k keysClient) ExportPKCS8(name, password string) (string, types.Error) {
+	formats, sdkErr := k.formats()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	pem, err := formats.ExportPKCS8(name, password)
+	return pem, types.Wrap(err)
+}
+
+func (k keysClient) ExportPublicKey(name, password string) (string, types.Error) {
+	pubKey, _, err := k.KeyManager.Find(name, password)
+	if err != nil {
+		return "", types.Wrap(err)
+	}
+
+	sm2PubKey, ok := pubKey.(*sm2.PubKey)
+	if !ok {
+		return "", types.Wrapf("only sm2 public keys can be exported as SubjectPublicKeyInfo")
+	}
+	bz, err := sm2.MarshalPublicKey(*sm2PubKey)
+	return string(bz), types.Wrap(err)
+}
+
+func (k keysClient) ImportPublicKey(name, password, pem string) (string, types.Error) {
+	pubKey, err := sm2.ParsePublicKey([]byte(pem))
+	if err != nil {
+		return "", types.Wrap(err)
+	}
+	watchOnly, sdkErr := k.watchOnly()
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	address, err := watchOnly.ImportPubKey(name, password, &pubKey)
+	return address, types.Wrap(err)
+}
+
+// Lock forgets the decrypted key, nothing is kept by a key manager without sessions
+func (k keysClient) Lock(name string) {
+	if sessions, ok := k.keys().(types.KeySessions); ok {
+		sessions.Lock(name)
+	}
+}
+
+func (k keysClient) Unlock(name, password string, ttl time.Duration) types.Error {
+	sessions, sdkErr := k.sessions()
+	if sdkErr != nil {
+		return sdkErr
+	}
+	return types.Wrap(sessions.Unlock(name, password, ttl))
+}
+
+// CreateHDWallet creates a wallet with the coin type of the configured BIP44 path, a mnemonic is
+// generated and returned when it is empty
+func (k keysClient) CreateHDWallet(name, password, mnemonic string) (string, string, types.Error) {
+	wallets, sdkErr := k.wallets()
+	if sdkErr != nil {
+		return "", "", sdkErr
+	}
+	address, mnemonic, err := wallets.CreateHDWallet(name, password, mnemonic, k.CoinType)
+	return address, mnemonic, types.Wrap(err)
+}
+
+// DeriveHDAccount derives the account and records it in the wallet
+func (k keysClient) DeriveHDAccount(name, password string, account, index uint32) (types.HDAccount, types.Error) {
+	wallets, sdkErr := k.wallets()
+	if sdkErr != nil {
+		return types.HDAccount{}, sdkErr
+	}
+	accounts, err := wallets.DeriveHDAccounts(name, password, account, index, 1)
+	if err != nil {
+		return types.HDAccount{}, types.Wrap(err)
+	}
+	if err := wallets.AddHDAccounts(name, password, accounts...); err != nil {
+		return types.HDAccount{}, types.Wrap(err)
+	}
+	return accounts[0], nil
+}
+
+func (k keysClient) HDAccounts(name, password string) ([]types.HDAccount, types.Error) {
+	wallets, sdkErr := k.wallets()
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	accounts, err := wallets.HDAccounts(name, password)
+	return accounts, types.Wrap(err)
+}
+
+// DiscoverHDAccounts scans the indices of the account until gapLimit consecutive accounts are
+// unknown to the chain, the used accounts are recorded in the wallet and returned
+func (k keysClient) DiscoverHDAccounts(name, password string, account uint32, gapLimit int) ([]types.HDAccount, types.Error) {
+	if gapLimit <= 0 {
+		return nil, types.Wrapf("gap limit must be positive")
+	}
+	grpcClient, ok := k.KeyManager.(types.GRPCClient)
+	if !ok {
+		return nil, types.Wrapf("account discovery requires a connected client")
+	}
+	wallets, sdkErr := k.wallets()
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	conn, err := grpcClient.GenConn()
+	if err != nil {
+		return nil, types.Wrap(err)
+	}
+	queryClient := auth.NewQueryClient(conn)
+
+	var (
+		used []types.HDAccount
+		gap  int
+	)
+	// the accounts are derived by batches of gapLimit, each batch decrypts the wallet once
+	for fromIndex := uint32(0); gap < gapLimit; fromIndex += uint32(gapLimit) {
+		accounts, err := wallets.DeriveHDAccounts(name, password, account, fromIndex, uint32(gapLimit))
+		if err != nil {
+			return nil, types.Wrap(err)
+		}
+
+		for _, hdAccount := range accounts {
+			if gap == gapLimit {
+				break
+			}
+			exists, err := accountExists(queryClient, hdAccount.Address)
+			if err != nil {
+				return nil, types.Wrap(err)
+			}
+			if !exists {
+				gap++
+				continue
+			}
+			used = append(used, hdAccount)
+			gap = 0
+		}
+	}
+
+	if len(used) > 0 {
+		if err := wallets.AddHDAccounts(name, password, used...); err != nil {
+			return nil, types.Wrap(err)
+		}
+	}
+	return used, nil
+}
+
+// accountExists reports whether the chain knows the account, the auth module answers NotFound otherwise
+func accountExists(queryClient auth.QueryClient, address string) (bool, error) {
+	_, err := queryClient.Account(context.Background(), &auth.QueryAccountRequest{Address: address})
+	switch {
+	case err == nil:
+		return true, nil
+	case status.Code(err) == codes.NotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmg "github.com/irisnet/core-sdk-go/common/crypto"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestKeystore(t *testing.T) {
+	scryptN := kmg.KeystoreScryptN
+	t.Cleanup(func() { kmg.KeystoreScryptN = scryptN })
+	kmg.KeystoreScryptN = 1 << 10
+
+	km := NewKeyManager(store.NewMemory(nil), ethsecp256k1.KeyType)
+	address, _, err := km.Insert("eth", "12345678")
+	require.NoError(t, err)
+
+	keystore, err := km.ExportKeystore("eth", "12345678", "")
+	require.NoError(t, err)
+
+	// the errors of reading the key are not reported as a missing key
+	_, err = km.ExportKeystore("eth", "87654321", "")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "not exist")
+	require.NoError(t, km.ImportAddress("watched", "12345678", address))
+	_, err = km.ExportKeystore("watched", "12345678", "")
+	require.True(t, isWatchOnlyError(err))
+
+	_, err = km.ImportKeystore("imported", "87654321", keystore)
+	require.Error(t, err)
+	imported, err := km.ImportKeystore("imported", "12345678", keystore)
+	require.NoError(t, err)
+	require.Equal(t, address, imported)
+
+	metadata, err := km.ListMetadata()
+	require.NoError(t, err)
+	require.Len(t, metadata, 3)
+	require.Equal(t, "imported", metadata[1].Name)
+	require.Equal(t, ethsecp256k1.KeyType, metadata[1].Algo)
+	require.True(t, strings.HasPrefix(metadata[1].EthAddress, "0x"))
+	require.Contains(t, keystore, strings.ToLower(metadata[1].EthAddress[2:]))
+
+	km = NewKeyManager(store.NewMemory(nil), "secp256k1")
+	_, _, err = km.Insert("cosmos", "12345678")
+	require.NoError(t, err)
+	_, err = km.ExportKeystore("cosmos", "12345678", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	lighthttp "github.com/tendermint/tendermint/light/provider/http"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	dbm "github.com/tendermint/tm-db"
+
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+	sdkrpc "github.com/irisnet/core-sdk-go/types/rpc"
+)
+
+const lightClientDBName = "light-client"
+
+var errNoLightClient = errors.New("no light client configured, see LightClientOption")
+
+// lightClient starts the light client on its first use, the trusted header is fetched from the node
+type lightClient struct {
+	mu     sync.Mutex
+	client *light.Client
+	cfg    sdktypes.ClientConfig
+	logger log.Logger
+}
+
+func newLightClient(cfg sdktypes.ClientConfig, logger log.Logger) *lightClient {
+	if cfg.LightClient == nil {
+		return nil
+	}
+	return &lightClient{cfg: cfg, logger: logger}
+}
+
+func (l *lightClient) get() (*light.Client, error) {
+	if l == nil {
+		return nil, errNoLightClient
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.client != nil {
+		return l.client, nil
+	}
+
+	primary, err := newLightProvider(l.cfg, l.cfg.RPCAddr)
+	if err != nil {
+		return nil, err
+	}
+	witnesses := make([]provider.Provider, len(l.cfg.LightClient.Witnesses))
+	for i, addr := range l.cfg.LightClient.Witnesses {
+		if witnesses[i], err = newLightProvider(l.cfg, addr); err != nil {
+			return nil, err
+		}
+	}
+
+	db := dbm.DB(dbm.NewMemDB())
+	if l.cfg.LightClient.DBPath != "" {
+		if db, err = dbm.NewGoLevelDB(lightClientDBName, l.cfg.LightClient.DBPath); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := startLightClient(l.cfg.ChainID, *l.cfg.LightClient, primary, witnesses, db, l.logger)
+	if err != nil {
+		return nil, err
+	}
+	l.client = client
+	return client, nil
+}
+
+// startLightClient restores the trusted headers of the db, or downloads the trusted header
+func startLightClient(chainID string, cfg sdktypes.LightClientConfig,
+	primary provider.Provider, witnesses []provider.Provider,
+	db dbm.DB, logger log.Logger,
+) (*light.Client, error) {
+	verification := light.SkippingVerification(cfg.TrustLevel)
+	if cfg.Sequential {
+		verification = light.SequentialVerification()
+	}
+
+	trustedStore := dbs.New(db, chainID)
+	// resume when the store verified from the same trusted header, NewClient would roll it back
+	if stored, err := trustedStore.LightBlock(cfg.TrustHeight); err == nil && bytes.Equal(stored.Hash(), cfg.TrustHash) {
+		return light.NewClientFromTrustedStore(chainID, cfg.TrustPeriod, primary, witnesses, trustedStore,
+			verification, light.Logger(logger))
+	}
+
+	trustOptions := light.TrustOptions{
+		Period: cfg.TrustPeriod,
+		Height: cfg.TrustHeight,
+		Hash:   cfg.TrustHash,
+	}
+	return light.NewClient(
+		context.Background(),
+		chainID,
+		trustOptions,
+		primary,
+		witnesses,
+		trustedStore,
+		verification,
+		light.Logger(logger),
+	)
+}
+
+func newLightProvider(cfg sdktypes.ClientConfig, rpcAddr string) (provider.Provider, error) {
+	client, err := sdkrpc.NewJSONRpcClient(rpcAddr, "", "/websocket", cfg.Timeout, cfg.Header)
+	if err != nil {
+		return nil, err
+	}
+	return lighthttp.NewWithClient(cfg.ChainID, client), nil
+}
+
+// TrustedHeader returns the header at the height verified by the light client, skipping or
+// sequentially from the trusted headers, and cross-checked with the witnesses. A height of 0
+// returns the latest header.
+func (base baseClient) TrustedHeader(height int64) (sdktypes.Header, error) {
+	client, err := base.light.get()
+	if err != nil {
+		return sdktypes.Header{}, err
+	}
+	return trustedHeader(client, height, time.Now())
+}
+
+func trustedHeader(client *light.Client, height int64, now time.Time) (sdktypes.Header, error) {
+	if height < 0 {
+		return sdktypes.Header{}, fmt.Errorf("negative height %d", height)
+	}
+
+	if height == 0 {
+		block, err := client.Update(context.Background(), now)
+		if err != nil {
+			return sdktypes.Header{}, err
+		}
+		// already the latest
+		if block == nil {
+			if height, err = client.LastTrustedHeight(); err != nil {
+				return sdktypes.Header{}, err
+			}
+			if block, err = client.TrustedLightBlock(height); err != nil {
+				return sdktypes.Header{}, err
+			}
+		}
+		return *block.Header, nil
+	}
+
+	block, err := client.VerifyLightBlockAtHeight(context.Background(), height, now)
+	if err != nil {
+		return sdktypes.Header{}, err
+	}
+	return *block.Header, nil
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	"github.com/tendermint/tendermint/light/provider"
+	"github.com/tendermint/tendermint/light/provider/mock"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+	dbm "github.com/tendermint/tm-db"
+
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+)
+
+const lightChainID = "light-chain"
+
+// genLightChain returns the headers of a chain of the given height signed by the validators,
+// the app hash of the blocks from fork on is forked to build a conflicting chain
+func genLightChain(t *testing.T, vals *tmtypes.ValidatorSet, privVals []tmtypes.PrivValidator,
+	genesis time.Time, height, fork int64,
+) *mock.Mock {
+	headers := make(map[int64]*tmtypes.SignedHeader, height)
+	valSets := make(map[int64]*tmtypes.ValidatorSet, height)
+
+	var lastBlockID tmtypes.BlockID
+	for h := int64(1); h <= height; h++ {
+		appHash := sha256.Sum256([]byte("app" + strconv.FormatInt(h, 10)))
+		if fork > 0 && h >= fork {
+			appHash = sha256.Sum256([]byte("fork" + strconv.FormatInt(h, 10)))
+		}
+
+		header := &tmtypes.Header{
+			Version:            tmversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            lightChainID,
+			Height:             h,
+			Time:               genesis.Add(time.Duration(h) * time.Minute),
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: vals.Hash(),
+			AppHash:            appHash[:],
+			ProposerAddress:    vals.Validators[0].Address,
+		}
+		blockID := tmtypes.BlockID{
+			Hash:          header.Hash(),
+			PartSetHeader: tmtypes.PartSetHeader{Total: 1, Hash: appHash[:]},
+		}
+		voteSet := tmtypes.NewVoteSet(lightChainID, h, 1, tmproto.PrecommitType, vals)
+		commit, err := tmtypes.MakeCommit(blockID, h, 1, voteSet, privVals, header.Time)
+		require.NoError(t, err)
+
+		headers[h] = &tmtypes.SignedHeader{Header: header, Commit: commit}
+		valSets[h] = vals
+		lastBlockID = blockID
+	}
+	return mock.New(lightChainID, headers, valSets)
+}
+
+func TestLightClient(t *testing.T) {
+	vals, privVals := tmtypes.RandValidatorSet(4, 10)
+	genesis := time.Now().Add(-time.Hour)
+	primary := genLightChain(t, vals, privVals, genesis, 10, 0)
+	witness := genLightChain(t, vals, privVals, genesis, 10, 0)
+
+	trusted, err := primary.LightBlock(context.Background(), 1)
+	require.NoError(t, err)
+	cfg := sdktypes.LightClientConfig{
+		TrustHeight: 1,
+		TrustHash:   trusted.Hash(),
+		TrustPeriod: 24 * time.Hour,
+		TrustLevel:  tmmath.Fraction{Numerator: 1, Denominator: 3},
+	}
+
+	for _, sequential := range []bool{false, true} {
+		cfg.Sequential = sequential
+		client, err := startLightClient(lightChainID, cfg, primary, []provider.Provider{witness}, dbm.NewMemDB(), log.NewNopLogger())
+		require.NoError(t, err)
+
+		expected, err := primary.LightBlock(context.Background(), 7)
+		require.NoError(t, err)
+		header, err := trustedHeader(client, 7, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, expected.Hash(), header.Hash())
+
+		header, err = trustedHeader(client, 0, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, int64(10), header.Height)
+
+		_, err = trustedHeader(client, 11, time.Now())
+		require.Error(t, err)
+	}
+
+	t.Run("wrong trust hash", func(t *testing.T) {
+		wrong := cfg
+		wrong.TrustHash = make([]byte, 32)
+		_, err := startLightClient(lightChainID, wrong, primary, []provider.Provider{witness}, dbm.NewMemDB(), log.NewNopLogger())
+		require.Error(t, err)
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		// the same validators signed another chain from the height 5
+		forked := genLightChain(t, vals, privVals, genesis, 10, 5)
+		client, err := startLightClient(lightChainID, cfg, primary, []provider.Provider{forked}, dbm.NewMemDB(), log.NewNopLogger())
+		require.NoError(t, err)
+
+		_, err = trustedHeader(client, 4, time.Now())
+		require.NoError(t, err)
+		_, err = trustedHeader(client, 8, time.Now())
+		require.Error(t, err)
+	})
+
+	t.Run("trust store", func(t *testing.T) {
+		db, err := dbm.NewGoLevelDB(lightClientDBName, t.TempDir())
+		require.NoError(t, err)
+		defer db.Close()
+
+		client, err := startLightClient(lightChainID, cfg, primary, []provider.Provider{witness}, db, log.NewNopLogger())
+		require.NoError(t, err)
+		_, err = trustedHeader(client, 6, time.Now())
+		require.NoError(t, err)
+
+		// the restarted client finds the verified header in the store, without asking the providers
+		dead := mock.NewDeadMock(lightChainID)
+		client, err = startLightClient(lightChainID, cfg, dead, []provider.Provider{dead}, db, log.NewNopLogger())
+		require.NoError(t, err)
+		block, err := client.TrustedLightBlock(6)
+		require.NoError(t, err)
+		require.Equal(t, int64(6), block.Height)
+	})
+}
+
+func TestLightClientOption(t *testing.T) {
+	var cfg sdktypes.ClientConfig
+	err := sdktypes.LightClientOption(sdktypes.LightClientConfig{TrustHeight: 1, TrustHash: make([]byte, 32)})(&cfg)
+	require.Error(t, err)
+
+	err = sdktypes.LightClientOption(sdktypes.LightClientConfig{
+		TrustHeight: 1,
+		TrustHash:   make([]byte, 32),
+		Witnesses:   []string{"http://localhost:36657"},
+	})(&cfg)
+	require.NoError(t, err)
+	require.Equal(t, 168*time.Hour, cfg.LightClient.TrustPeriod)
+	require.Equal(t, uint64(3), cfg.LightClient.TrustLevel.Denominator)
+
+	base := baseClient{}
+	_, err = base.TrustedHeader(1)
+	require.Equal(t, errNoLightClient, err)
+	_, err = base.VerifiedQueryStore([]byte("key"), "bank", 1)
+	require.Equal(t, errNoLightClient, err)
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	sdkrpc "github.com/irisnet/core-sdk-go/types/rpc"
+	typetx "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+const (
+	// tendermint caps the number of txs returned by `unconfirmed_txs`
+	maxUnconfirmedTxs      = 100
+	defaultMempoolInterval = 1 * time.Second
+	evictionConfirmedPolls = 3
+)
+
+// QueryUnconfirmedTxs returns the decoded transactions currently in the mempool that match the filter.
+// At most 100 transactions are inspected, which is the limit enforced by tendermint.
+func (base baseClient) QueryUnconfirmedTxs(filter sdk.MempoolFilter, limit int) (sdk.ResultUnconfirmedTxs, error) {
+	if limit <= 0 || limit > maxUnconfirmedTxs {
+		limit = maxUnconfirmedTxs
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(base.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	res, err := base.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return sdk.ResultUnconfirmedTxs{}, err
+	}
+
+	var txs []sdk.PendingTx
+	for _, bz := range res.Txs {
+		tx, err := base.parsePendingTx(bz)
+		if err != nil {
+			base.Logger().Debug("decode pending tx failed", "hash", sdk.HexStringFrom(tmhash.Sum(bz)), "errMsg", err.Error())
+			continue
+		}
+		if filter.Match(tx) {
+			txs = append(txs, tx)
+		}
+	}
+
+	return sdk.ResultUnconfirmedTxs{
+		Count:      res.Count,
+		Total:      res.Total,
+		TotalBytes: res.TotalBytes,
+		Txs:        txs,
+	}, nil
+}
+
+// QueryNumUnconfirmedTxs returns the size of the mempool without the transactions
+func (base baseClient) QueryNumUnconfirmedTxs() (sdk.ResultUnconfirmedTxs, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(base.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	res, err := base.NumUnconfirmedTxs(ctx)
+	if err != nil {
+		return sdk.ResultUnconfirmedTxs{}, err
+	}
+	return sdk.ResultUnconfirmedTxs{
+		Count:      res.Count,
+		Total:      res.Total,
+		TotalBytes: res.TotalBytes,
+	}, nil
+}
+
+// SubscribePendingTx polls the mempool every interval and notifies the handler when a matching
+// transaction enters the mempool, and later when it is committed or evicted.
+// Tendermint has no mempool event stream, so the watcher diffs consecutive snapshots.
+func (base baseClient) SubscribePendingTx(filter sdk.MempoolFilter, interval time.Duration, handler sdk.EventPendingTxHandler) (sdk.MempoolSubscription, sdk.Error) {
+	if handler == nil {
+		return sdk.MempoolSubscription{}, sdk.Wrapf("handler is required")
+	}
+	if interval <= 0 {
+		interval = defaultMempoolInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &mempoolWatcher{
+		base:     base,
+		filter:   filter,
+		handler:  handler,
+		interval: interval,
+		pending:  make(map[string]*watchedTx),
+	}
+
+	subscription := sdk.MempoolSubscription{
+		ID:     getSubscriber(),
+		Cancel: cancel,
+	}
+	base.Logger().Info("subscribe mempool", "subscriber", subscription.ID)
+
+	go w.run(ctx, subscription.ID)
+	return subscription, nil
+}
+
+func (base baseClient) parsePendingTx(bz tmtypes.Tx) (sdk.PendingTx, error) {
+	tx, err := base.encodingConfig.TxConfig.TxDecoder()(bz)
+	if err != nil {
+		return sdk.PendingTx{}, err
+	}
+
+	unwrappedTx, err := typetx.Unwrap(base.Marshaler(), tx)
+	if err != nil {
+		return sdk.PendingTx{}, err
+	}
+
+	var signers []string
+	seen := make(map[string]bool)
+	for _, msg := range unwrappedTx.Body.Msgs {
+		for _, addr := range msg.GetSigners() {
+			if !seen[addr.String()] {
+				signers = append(signers, addr.String())
+				seen[addr.String()] = true
+			}
+		}
+	}
+
+	sequences := make([]uint64, len(unwrappedTx.AuthInfo.Signatures))
+	for i, sig := range unwrappedTx.AuthInfo.Signatures {
+		sequences[i] = sig.Sequence
+	}
+
+	pendingTx := sdk.PendingTx{
+		Hash:      sdk.HexBytes(tmhash.Sum(bz)).String(),
+		Signers:   signers,
+		Sequences: sequences,
+		Memo:      unwrappedTx.Body.Memo,
+		Msgs:      unwrappedTx.Body.Msgs,
+	}
+	if fee := unwrappedTx.AuthInfo.Fee; fee != nil {
+		pendingTx.Fee = fee.Amount
+		pendingTx.Gas = fee.Gas
+	}
+	return pendingTx, nil
+}
+
+type watchedTx struct {
+	tx sdk.PendingTx
+	// number of consecutive polls in which the tx was neither in the mempool nor indexed
+	missing int
+}
+
+type mempoolWatcher struct {
+	base     baseClient
+	filter   sdk.MempoolFilter
+	handler  sdk.EventPendingTxHandler
+	interval time.Duration
+	pending  map[string]*watchedTx
+}
+
+func (w *mempoolWatcher) run(ctx context.Context, subscriber string) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			w.base.Logger().Info("end to subscribe mempool", "subscriber", subscriber)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *mempoolWatcher) poll(ctx context.Context) {
+	defer sdk.CatchPanic(func(errMsg string) {
+		w.base.Logger().Error("mempool watcher panic", "errMsg", errMsg)
+	})
+
+	res, err := w.base.QueryUnconfirmedTxs(w.filter, maxUnconfirmedTxs)
+	if err != nil {
+		w.base.Logger().Error("query unconfirmed txs failed", "errMsg", err.Error())
+		return
+	}
+
+	// the snapshot only holds the first txs of a busy mempool, a tx missing from a partial snapshot
+	// may still be pending behind them
+	complete := res.Total <= res.Count
+
+	current := make(map[string]bool, len(res.Txs))
+	for _, tx := range res.Txs {
+		current[tx.Hash] = true
+		if _, ok := w.pending[tx.Hash]; ok {
+			w.pending[tx.Hash].missing = 0
+			continue
+		}
+		w.pending[tx.Hash] = &watchedTx{tx: tx}
+		w.handler(sdk.EventDataPendingTx{
+			Status: sdk.PendingTxAdded,
+			Tx:     tx,
+		})
+	}
+
+	for hash, watched := range w.pending {
+		if current[hash] || ctx.Err() != nil {
+			continue
+		}
+		w.resolve(ctx, hash, watched, complete)
+	}
+}
+
+// resolve decides whether a transaction that left the mempool was committed or evicted.
+// The tx indexer may lag behind the mempool update, so eviction is only reported after
+// the transaction has been missing for several consecutive polls of the whole mempool.
+func (w *mempoolWatcher) resolve(ctx context.Context, hash string, watched *watchedTx, complete bool) {
+	bz, err := hex.DecodeString(hash)
+	if err != nil {
+		delete(w.pending, hash)
+		return
+	}
+
+	res, err := w.base.Tx(ctx, bz, false)
+	if err == nil {
+		delete(w.pending, hash)
+		w.handler(sdk.EventDataPendingTx{
+			Status: sdk.PendingTxCommitted,
+			Tx:     watched.tx,
+			Height: res.Height,
+			Result: sdk.TxResult{
+				Code:      res.TxResult.Code,
+				Log:       res.TxResult.Log,
+				GasWanted: res.TxResult.GasWanted,
+				GasUsed:   res.TxResult.GasUsed,
+				Events:    sdk.StringifyEvents(res.TxResult.Events),
+			},
+		})
+		return
+	}
+
+	if !sdkrpc.IsTxNotFound(err, bz) {
+		w.base.Logger().Debug("query pending tx failed", "hash", hash, "errMsg", err.Error())
+		return
+	}
+	if !complete {
+		return
+	}
+
+	watched.missing++
+	if watched.missing < evictionConfirmedPolls {
+		return
+	}
+
+	delete(w.pending, hash)
+	w.handler(sdk.EventDataPendingTx{
+		Status: sdk.PendingTxEvicted,
+		Tx:     watched.tx,
+	})
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	commoncodec "github.com/irisnet/core-sdk-go/common/codec"
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	commoncryptocodec "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+	sdkrpc "github.com/irisnet/core-sdk-go/types/rpc"
+	txtypes "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+// mempoolNode serves a snapshot of its mempool and the txs committed in its blocks
+type mempoolNode struct {
+	sdktypes.TmClient
+	mempool []tmtypes.Tx
+	// total is the size of the mempool, the snapshot is partial when it is larger
+	total     int
+	committed map[string]int64
+	// txErr answers the tx queries of the uncommitted txs instead of not found
+	txErr error
+}
+
+func (n *mempoolNode) UnconfirmedTxs(context.Context, *int) (*ctypes.ResultUnconfirmedTxs, error) {
+	total := n.total
+	if total < len(n.mempool) {
+		total = len(n.mempool)
+	}
+	return &ctypes.ResultUnconfirmedTxs{Count: len(n.mempool), Total: total, Txs: n.mempool}, nil
+}
+
+func (n *mempoolNode) Tx(_ context.Context, hash []byte, _ bool) (*ctypes.ResultTx, error) {
+	if height, ok := n.committed[fmt.Sprintf("%X", hash)]; ok {
+		return &ctypes.ResultTx{Height: height}, nil
+	}
+	if n.txErr != nil {
+		return nil, n.txErr
+	}
+	return nil, fmt.Errorf("request failed, %w", &sdkrpc.RPCError{
+		Code:    -32603,
+		Message: "Internal error",
+		Data:    fmt.Sprintf("tx (%X) not found", hash),
+	})
+}
+
+func (n *mempoolNode) commit(tx tmtypes.Tx, height int64) {
+	n.remove(tx)
+	n.committed[fmt.Sprintf("%X", tmhash.Sum(tx))] = height
+}
+
+func (n *mempoolNode) remove(tx tmtypes.Tx) {
+	for i, pending := range n.mempool {
+		if string(pending) == string(tx) {
+			n.mempool = append(n.mempool[:i], n.mempool[i+1:]...)
+			return
+		}
+	}
+}
+
+type mempoolTest struct {
+	t       *testing.T
+	node    *mempoolNode
+	watcher *mempoolWatcher
+	events  []sdktypes.EventDataPendingTx
+	txCfg   sdktypes.TxConfig
+}
+
+// newTxEncodingConfig decodes the txs of the bank msgs
+func newTxEncodingConfig() sdktypes.EncodingConfig {
+	registry := codectypes.NewInterfaceRegistry()
+	registry.RegisterInterface("cosmos.v1beta1.Msg", (*sdktypes.Msg)(nil))
+	txtypes.RegisterInterfaces(registry)
+	commoncryptocodec.RegisterInterfaces(registry)
+	bank.RegisterInterfaces(registry)
+	marshaler := commoncodec.NewProtoCodec(registry)
+
+	return sdktypes.EncodingConfig{
+		InterfaceRegistry: registry,
+		Marshaler:         marshaler,
+		TxConfig:          txtypes.NewTxConfig(marshaler, txtypes.DefaultSignModes),
+	}
+}
+
+// encodeSend returns the unsigned tx of a send
+func encodeSend(t *testing.T, txCfg sdktypes.TxConfig, from, to sdktypes.AccAddress, memo string) tmtypes.Tx {
+	builder := txCfg.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(bank.NewMsgSend(from, to, sdktypes.NewCoins(sdktypes.NewInt64Coin("uiris", 1)))))
+	builder.SetMemo(memo)
+	tx, err := txCfg.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+	return tx
+}
+
+func newMempoolTest(t *testing.T, filter sdktypes.MempoolFilter) *mempoolTest {
+	encodingConfig := newTxEncodingConfig()
+	m := &mempoolTest{
+		t:     t,
+		node:  &mempoolNode{committed: make(map[string]int64)},
+		txCfg: encodingConfig.TxConfig,
+	}
+	m.watcher = &mempoolWatcher{
+		base: baseClient{
+			TmClient:       m.node,
+			cfg:            &sdktypes.ClientConfig{Timeout: 5},
+			encodingConfig: encodingConfig,
+			AccountQuery:   AccountQuery{Logger: log.NewNopLogger()},
+		},
+		filter: filter,
+		handler: func(event sdktypes.EventDataPendingTx) {
+			m.events = append(m.events, event)
+		},
+		pending: make(map[string]*watchedTx),
+	}
+	return m
+}
+
+// send adds a tx of a send to the mempool
+func (m *mempoolTest) send(from, to sdktypes.AccAddress, memo string) tmtypes.Tx {
+	tx := encodeSend(m.t, m.txCfg, from, to, memo)
+	m.node.mempool = append(m.node.mempool, tx)
+	return tx
+}
+
+// poll returns the statuses notified by a poll of the mempool
+func (m *mempoolTest) poll() []sdktypes.PendingTxStatus {
+	m.events = nil
+	m.watcher.poll(context.Background())
+
+	statuses := make([]sdktypes.PendingTxStatus, len(m.events))
+	for i, event := range m.events {
+		statuses[i] = event.Status
+	}
+	return statuses
+}
+
+func TestMempoolWatcher(t *testing.T) {
+	from := sdktypes.AccAddress([]byte("from________________"))
+	to := sdktypes.AccAddress([]byte("to__________________"))
+	other := sdktypes.AccAddress([]byte("other_______________"))
+	m := newMempoolTest(t, sdktypes.MempoolFilter{Addresses: []string{to.String()}})
+
+	committed := m.send(from, to, "committed")
+	evicted := m.send(from, to, "evicted")
+	m.send(from, other, "filtered")
+	require.Equal(t, []sdktypes.PendingTxStatus{sdktypes.PendingTxAdded, sdktypes.PendingTxAdded}, m.poll())
+	require.Equal(t, "committed", m.events[0].Tx.Memo)
+	require.Empty(t, m.poll(), "a tx is added once")
+
+	// a committed tx is reported with its block
+	m.node.commit(committed, 12)
+	require.Equal(t, []sdktypes.PendingTxStatus{sdktypes.PendingTxCommitted}, m.poll())
+	require.Equal(t, int64(12), m.events[0].Height)
+	require.Equal(t, "committed", m.events[0].Tx.Memo)
+
+	// an evicted tx is reported once it is missing from evictionConfirmedPolls snapshots
+	m.node.remove(evicted)
+	for i := 1; i < evictionConfirmedPolls; i++ {
+		require.Empty(t, m.poll())
+	}
+	require.Equal(t, []sdktypes.PendingTxStatus{sdktypes.PendingTxEvicted}, m.poll())
+	require.Equal(t, "evicted", m.events[0].Tx.Memo)
+	require.Empty(t, m.watcher.pending)
+}
+
+func TestMempoolWatcherUnresolvedTx(t *testing.T) {
+	from := sdktypes.AccAddress([]byte("from________________"))
+	m := newMempoolTest(t, sdktypes.MempoolFilter{})
+
+	tx := m.send(from, from, "pending")
+	require.Equal(t, []sdktypes.PendingTxStatus{sdktypes.PendingTxAdded}, m.poll())
+	m.node.remove(tx)
+
+	// a partial snapshot doesn't tell whether the tx left the mempool
+	m.node.total = maxUnconfirmedTxs + 1
+	for i := 0; i < 2*evictionConfirmedPolls; i++ {
+		require.Empty(t, m.poll())
+	}
+	require.Zero(t, m.watcher.pending[fmt.Sprintf("%X", tmhash.Sum(tx))].missing)
+
+	// nor does a failed tx query
+	m.node.total = 0
+	m.node.txErr = errors.New("connection refused")
+	for i := 0; i < 2*evictionConfirmedPolls; i++ {
+		require.Empty(t, m.poll())
+	}
+
+	// the count of the missing polls restarts when the tx is seen again
+	m.node.txErr = nil
+	for i := 1; i < evictionConfirmedPolls; i++ {
+		require.Empty(t, m.poll())
+	}
+	m.node.mempool = append(m.node.mempool, tx)
+	require.Empty(t, m.poll())
+	m.node.remove(tx)
+	for i := 1; i < evictionConfirmedPolls; i++ {
+		require.Empty(t, m.poll())
+	}
+	require.Equal(t, []sdktypes.PendingTxStatus{sdktypes.PendingTxEvicted}, m.poll())
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestPKCS8(t *testing.T) {
+	iterations := sm2.PBKDF2Iterations
+	t.Cleanup(func() { sm2.PBKDF2Iterations = iterations })
+	sm2.PBKDF2Iterations = 1024
+
+	km := NewKeyManager(store.NewMemory(nil), "sm2")
+	address, _, err := km.Insert("gm", "12345678")
+	require.NoError(t, err)
+
+	pem, err := km.ExportPKCS8("gm", "12345678")
+	require.NoError(t, err)
+
+	_, err = km.ImportPKCS8("imported", "87654321", pem)
+	require.Error(t, err)
+	imported, err := km.ImportPKCS8("imported", "12345678", pem)
+	require.NoError(t, err)
+	require.Equal(t, address, imported)
+
+	signature, pubKey, err := km.Sign("imported", "12345678", []byte("data"))
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature([]byte("data"), signature))
+
+	client := keysClient{BIP44Params: *hd.NewFundraiserParams(0, 118, 0), KeyManager: km}
+	pubKeyPEM, sdkErr := client.ExportPublicKey("imported", "12345678")
+	require.NoError(t, sdkErr)
+	parsed, err := sm2.ParsePublicKey([]byte(pubKeyPEM))
+	require.NoError(t, err)
+	require.True(t, sm2.VerifyGMSignature(parsed, []byte("data"), signature, nil))
+
+	watched, sdkErr := client.ImportPublicKey("watched", "12345678", pubKeyPEM)
+	require.NoError(t, sdkErr)
+	require.Equal(t, address, watched)
+	metadata, err := km.ListMetadata()
+	require.NoError(t, err)
+	require.Equal(t, "watched", metadata[2].Name)
+	require.True(t, metadata[2].WatchOnly)
+	_, sdkErr = client.ImportPublicKey("invalid", "12345678", "not a pem")
+	require.Error(t, sdkErr)
+
+	// the errors of reading the key are not reported as a missing key
+	_, err = km.ExportPKCS8("gm", "87654321")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "not exist")
+	_, err = km.ExportPKCS8("watched", "12345678")
+	require.True(t, isWatchOnlyError(err))
+
+	km = NewKeyManager(store.NewMemory(nil), "secp256k1")
+	_, _, err = km.Insert("cosmos", "12345678")
+	require.NoError(t, err)
+	_, err = km.ExportPKCS8("cosmos", "12345678")
+	require.Error(t, err)
+}
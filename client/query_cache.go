@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"github.com/gogo/protobuf/proto"
+	"github.com/tendermint/tendermint/libs/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	commoncache "github.com/irisnet/core-sdk-go/common/cache"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+)
+
+const querySubscribeRetry = 10 * time.Second
+
+// the kinds of the cached queries prefix their keys, each kind caches its own type of response
+const (
+	queryKindABCI  = "abci"  // cachedResponse of Query
+	queryKindStore = "store" // abci.ResponseQuery of QueryStore
+	queryKindGRPC  = "grpc"  // cachedResponse of the gRPC queries
+)
+
+// queryCache keeps the query responses by method, request and height. The latest height follows
+// the new block headers, so the responses of the previous block are no longer hit once a block is
+// committed, the params of the latest height are kept until they expire. The queries building the
+// txs are never cached, see isUncachedQuery.
+type queryCache struct {
+	cache            commoncache.Cache
+	paramsExpiration time.Duration
+	tm               sdktypes.TmClient
+	logger           log.Logger
+
+	height int64
+
+	mu         sync.Mutex
+	subscribed bool
+	retryAt    time.Time
+}
+
+// cachedResponse is the value of a query and the height it was served at
+type cachedResponse struct {
+	value  []byte
+	height int64
+}
+
+func newQueryCache(cfg sdktypes.ClientConfig, tm sdktypes.TmClient, logger log.Logger) *queryCache {
+	if cfg.QueryCache == nil {
+		return nil
+	}
+	return &queryCache{
+		cache:            commoncache.NewCache(cfg.QueryCache.Capacity, true),
+		paramsExpiration: cfg.QueryCache.ParamsExpiration,
+		tm:               tm,
+		logger:           logger,
+	}
+}
+
+// get returns the response of the request at the pinned height, the latest one when 0
+func (c *queryCache) get(kind, method string, request []byte, pinned int64) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	key, ok := c.key(kind, method, request, pinned, c.latest())
+	if !ok {
+		return nil, false
+	}
+	value, err := c.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// set keeps the response of the request at the pinned height, or at the height it was served at
+func (c *queryCache) set(kind, method string, request []byte, pinned, served int64, response interface{}) {
+	if c == nil {
+		return
+	}
+	c.advance(served)
+	if served <= 0 {
+		served = atomic.LoadInt64(&c.height)
+	}
+
+	key, ok := c.key(kind, method, request, pinned, served)
+	if !ok {
+		return
+	}
+	if pinned <= 0 && isParamsQuery(method) {
+		_ = c.cache.SetWithExpire(key, response, c.paramsExpiration)
+		return
+	}
+	_ = c.cache.Set(key, response)
+}
+
+func (c *queryCache) key(kind, method string, request []byte, pinned, latest int64) (string, bool) {
+	switch {
+	case isUncachedQuery(method):
+		return "", false
+	case pinned > 0:
+		return fmt.Sprintf("%s/%s/%d/%X", kind, method, pinned, request), true
+	case isParamsQuery(method):
+		return fmt.Sprintf("%s/%s/params/%X", kind, method, request), true
+	case latest > 0:
+		return fmt.Sprintf("%s/%s/%d/%X", kind, method, latest, request), true
+	default:
+		return "", false
+	}
+}
+
+// latest returns the height of the latest block, 0 until it is known
+func (c *queryCache) latest() int64 {
+	c.mu.Lock()
+	if !c.subscribed && time.Now().After(c.retryAt) {
+		if err := c.subscribe(); err != nil {
+			c.logger.Error("subscribe new block header for the query cache failed", "errMsg", err.Error())
+			c.retryAt = time.Now().Add(querySubscribeRetry)
+		} else {
+			c.subscribed = true
+		}
+	}
+	c.mu.Unlock()
+	return atomic.LoadInt64(&c.height)
+}
+
+func (c *queryCache) subscribe() error {
+	if _, err := c.tm.SubscribeNewBlockHeader(func(block sdktypes.EventDataNewBlockHeader) {
+		c.advance(block.Header.Height)
+	}); err != nil {
+		return err
+	}
+
+	status, err := c.tm.Status(context.Background())
+	if err != nil {
+		return err
+	}
+	c.advance(status.SyncInfo.LatestBlockHeight)
+	return nil
+}
+
+// advance moves the latest height forward, the responses served at a higher height advance it too
+// when the new block header is late
+func (c *queryCache) advance(height int64) {
+	for {
+		latest := atomic.LoadInt64(&c.height)
+		if height <= latest || atomic.CompareAndSwapInt64(&c.height, latest, height) {
+			return
+		}
+	}
+}
+
+// uncachedQueries are the queries of the accounts building the txs, their sequence changes within a
+// block, e.g. the account is refreshed after a wrong sequence
+var uncachedQueries = map[string]bool{
+	"/cosmos.auth.v1beta1.Query/Account":     true,
+	"/cosmos.bank.v1beta1.Query/AllBalances": true,
+}
+
+// isUncachedQuery returns true for the queries always served by the node, the account queries and
+// the methods of the tx service, e.g. Simulate and BroadcastTx
+func isUncachedQuery(method string) bool {
+	return uncachedQueries[method] || strings.HasPrefix(method, "/cosmos.tx.v1beta1.Service/")
+}
+
+// isParamsQuery returns true for the gRPC Params methods and the ABCI params paths
+func isParamsQuery(method string) bool {
+	method = strings.ToLower(method)
+	return strings.HasSuffix(method, "/params") || strings.Contains(method, "/params/")
+}
+
+// cachedGRPCClient serves the gRPC queries from the cache
+type cachedGRPCClient struct {
+	sdktypes.GRPCClient
+	cache *queryCache
+}
+
+func (c cachedGRPCClient) GenConn() (grpc1.ClientConn, error) {
+	conn, err := c.GRPCClient.GenConn()
+	if err != nil {
+		return nil, err
+	}
+	return cachedConn{ClientConn: conn, cache: c.cache}, nil
+}
+
+type cachedConn struct {
+	grpc1.ClientConn
+	cache *queryCache
+}
+
+// gogoMessage is a message generated by gogoproto, the others, e.g. the dynamic messages of
+// GRPCQuery, are not cached
+type gogoMessage interface {
+	proto.Message
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+func (c cachedConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	request, ok := args.(gogoMessage)
+	response, ok2 := reply.(gogoMessage)
+	if !ok || !ok2 {
+		return c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	}
+	bz, err := request.Marshal()
+	if err != nil {
+		return err
+	}
+
+	// the height pinned by WithHeight
+	var pinned int64
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(GRPCBlockHeightHeader); len(values) > 0 {
+		pinned, _ = strconv.ParseInt(values[0], 10, 64)
+	}
+
+	if value, ok := c.cache.get(queryKindGRPC, method, bz, pinned); ok {
+		if cached, ok := value.(cachedResponse); ok {
+			setHeightHeader(opts, cached.height)
+			response.Reset()
+			return response.Unmarshal(cached.value)
+		}
+	}
+
+	var header metadata.MD
+	if err := c.ClientConn.Invoke(ctx, method, args, reply, append(opts, grpc.Header(&header))...); err != nil {
+		return err
+	}
+	var served int64
+	if values := header.Get(GRPCBlockHeightHeader); len(values) > 0 {
+		served, _ = strconv.ParseInt(values[0], 10, 64)
+	}
+	if value, err := response.Marshal(); err == nil {
+		c.cache.set(queryKindGRPC, method, bz, pinned, served, cachedResponse{value: value, height: served})
+	}
+	return nil
+}
+
+// setHeightHeader answers the header options of a cached response like the node would
+func setHeightHeader(opts []grpc.CallOption, height int64) {
+	if height <= 0 {
+		return
+	}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs(GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+		}
+	}
+}
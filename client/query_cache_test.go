@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	grpc1 "github.com/gogo/protobuf/grpc"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	commoncache "github.com/irisnet/core-sdk-go/common/cache"
+	commoncodec "github.com/irisnet/core-sdk-go/common/codec"
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	commoncryptocodec "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/auth"
+	"github.com/irisnet/core-sdk-go/types/query"
+	"github.com/irisnet/core-sdk-go/types/store"
+	txtypes "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+// cacheNode serves the queries at its height and counts them
+type cacheNode struct {
+	sdktypes.TmClient
+	grpc1.ClientConn
+	height   int64
+	calls    int
+	onHeader sdktypes.EventNewBlockHeaderHandler
+	// the account served by the auth and bank queries
+	sequence   uint64
+	denoms     int
+	broadcasts int
+}
+
+func (n *cacheNode) GenConn() (grpc1.ClientConn, error) {
+	return n, nil
+}
+
+func (n *cacheNode) Invoke(ctx context.Context, _ string, args, reply interface{}, opts ...grpc.CallOption) error {
+	n.calls++
+	served := n.height
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(GRPCBlockHeightHeader); len(values) > 0 {
+		served, _ = strconv.ParseInt(values[0], 10, 64)
+	}
+
+	switch reply := reply.(type) {
+	case *bank.QuerySupplyOfResponse:
+		reply.Amount = sdktypes.NewInt64Coin(args.(*bank.QuerySupplyOfRequest).Denom, served)
+	case *bank.QueryParamsResponse:
+		reply.Params.DefaultSendEnabled = true
+	case *auth.QueryAccountResponse:
+		account, err := codectypes.NewAnyWithValue(&auth.BaseAccount{
+			Address:       args.(*auth.QueryAccountRequest).Address,
+			AccountNumber: 1,
+			Sequence:      n.sequence,
+		})
+		if err != nil {
+			return err
+		}
+		reply.Account = account
+	case *bank.QueryAllBalancesResponse:
+		// a page of denoms from the key, the index of the first one
+		pageReq := args.(*bank.QueryAllBalancesRequest).Pagination
+		start, _ := strconv.Atoi(string(pageReq.GetKey()))
+		end := start + int(pageReq.GetLimit())
+		if pageReq.GetLimit() == 0 || end > n.denoms {
+			end = n.denoms
+		}
+		for i := start; i < end; i++ {
+			reply.Balances = append(reply.Balances, sdktypes.NewInt64Coin(fmt.Sprintf("denom%03d", i), int64(i+1)))
+		}
+		reply.Pagination = &query.PageResponse{}
+		if end < n.denoms {
+			reply.Pagination.NextKey = []byte(strconv.Itoa(end))
+		}
+	}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs(GRPCBlockHeightHeader, strconv.FormatInt(served, 10))
+		}
+	}
+	return nil
+}
+
+func (n *cacheNode) ABCIQueryWithOptions(_ context.Context, _ string, _ tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	n.calls++
+	height := opts.Height
+	if height == 0 {
+		height = n.height
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Height: height, Value: []byte(strconv.FormatInt(height, 10))}}, nil
+}
+
+func (n *cacheNode) SubscribeNewBlockHeader(handler sdktypes.EventNewBlockHeaderHandler) (sdktypes.Subscription, sdktypes.Error) {
+	n.onHeader = handler
+	return sdktypes.Subscription{}, nil
+}
+
+func (n *cacheNode) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: n.height}}, nil
+}
+
+// BroadcastTxSync answers a wrong sequence unless the tx is signed with the sequence of the account
+func (n *cacheNode) BroadcastTxSync(_ context.Context, bz tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	n.broadcasts++
+	var raw txtypes.TxRaw
+	if err := raw.Unmarshal(bz); err != nil {
+		return nil, err
+	}
+	var authInfo txtypes.AuthInfo
+	if err := authInfo.Unmarshal(raw.AuthInfoBytes); err != nil {
+		return nil, err
+	}
+	if authInfo.SignerInfos[0].Sequence != n.sequence {
+		return &ctypes.ResultBroadcastTx{Codespace: sdktypes.RootCodespace, Code: uint32(sdktypes.WrongSequence)}, nil
+	}
+	n.sequence++
+	return &ctypes.ResultBroadcastTx{}, nil
+}
+
+func (n *cacheNode) commit() {
+	n.height++
+	n.onHeader(sdktypes.EventDataNewBlockHeader{Header: sdktypes.Header{Height: n.height}})
+}
+
+func newCachedClient(node *cacheNode, paramsExpiration time.Duration) *baseClient {
+	cfg := sdktypes.ClientConfig{QueryCache: &sdktypes.QueryCacheConfig{Capacity: 100, ParamsExpiration: paramsExpiration}}
+	cache := newQueryCache(cfg, node, log.NewNopLogger())
+	base := &baseClient{
+		TmClient:   node,
+		cfg:        &cfg,
+		queryCache: cache,
+		AccountQuery: AccountQuery{
+			GRPCClient: cachedGRPCClient{GRPCClient: node, cache: cache},
+			Logger:     log.NewNopLogger(),
+			Cache:      commoncache.NewCache(cacheCapacity, true),
+			cdc:        newAccountCodec(),
+			expiration: cacheExpirePeriod,
+		},
+	}
+	base.AccountQuery.Queries = base
+	return base
+}
+
+// newAccountCodec unpacks the accounts of the auth queries
+func newAccountCodec() commoncodec.Marshaler {
+	registry := codectypes.NewInterfaceRegistry()
+	bank.RegisterInterfaces(registry)
+	return commoncodec.NewProtoCodec(registry)
+}
+
+func supplyOf(t *testing.T, base sdktypes.BaseClient, denom string) int64 {
+	conn, err := base.GenConn()
+	require.NoError(t, err)
+	res, err := bank.NewQueryClient(conn).SupplyOf(context.Background(), &bank.QuerySupplyOfRequest{Denom: denom})
+	require.NoError(t, err)
+	return res.Amount.Amount.Int64()
+}
+
+func TestQueryCache(t *testing.T) {
+	node := &cacheNode{height: 10}
+	base := newCachedClient(node, time.Minute)
+
+	require.Equal(t, int64(10), supplyOf(t, base, "stake"))
+	require.Equal(t, int64(10), supplyOf(t, base, "stake"))
+	require.Equal(t, 1, node.calls)
+
+	// another request is another entry
+	supplyOf(t, base, "iris")
+	require.Equal(t, 2, node.calls)
+
+	// a new block drops the responses of the previous one
+	node.commit()
+	require.Equal(t, int64(11), supplyOf(t, base, "stake"))
+	require.Equal(t, 3, node.calls)
+
+	// the params are kept across the blocks
+	conn, err := base.GenConn()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		res, err := bank.NewQueryClient(conn).Params(context.Background(), &bank.QueryParamsRequest{})
+		require.NoError(t, err)
+		require.True(t, res.Params.DefaultSendEnabled)
+		node.commit()
+	}
+	require.Equal(t, 4, node.calls)
+
+	// the pinned queries are kept for the pinned height, with its served height
+	pinned, err := base.WithHeight(5)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), supplyOf(t, pinned, "stake"))
+	node.commit()
+	require.Equal(t, int64(5), supplyOf(t, pinned, "stake"))
+	require.Equal(t, int64(5), pinned.ServedHeight())
+	require.Equal(t, 5, node.calls)
+
+	// the ABCI queries
+	for i := 0; i < 2; i++ {
+		res, err := base.QueryStore([]byte("key"), "bank", 0, false)
+		require.NoError(t, err)
+		require.Equal(t, node.height, res.Height)
+	}
+	require.Equal(t, 6, node.calls)
+	_, err = base.QueryStore([]byte("key"), "bank", 0, true)
+	require.NoError(t, err)
+	require.Equal(t, 7, node.calls)
+	_, err = base.Query("/custom/bank/total", nil)
+	require.NoError(t, err)
+	_, err = base.Query("/custom/bank/total", nil)
+	require.NoError(t, err)
+	require.Equal(t, 8, node.calls)
+}
+
+func TestQueryCacheParamsExpiration(t *testing.T) {
+	node := &cacheNode{height: 10}
+	base := newCachedClient(node, 10*time.Millisecond)
+
+	_, err := base.Query("custom/gov/params/voting", nil)
+	require.NoError(t, err)
+	_, err = base.Query("custom/gov/params/voting", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, node.calls)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err = base.Query("custom/gov/params/voting", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, node.calls)
+}
+
+func TestBuildAndSendRetriesWrongSequence(t *testing.T) {
+	node := &cacheNode{height: 1, sequence: 1}
+	base := newCachedClient(node, time.Minute)
+	base.cfg.ChainID = "test"
+	base.cfg.Mode = sdktypes.Sync
+	base.cfg.Gas = 200000
+	base.l = NewLocker(concurrency)
+	base.TokenManager = sdktypes.DefaultTokenManager{}
+
+	registry := codectypes.NewInterfaceRegistry()
+	bank.RegisterInterfaces(registry)
+	commoncryptocodec.RegisterInterfaces(registry)
+	base.encodingConfig.TxConfig = txtypes.NewTxConfig(commoncodec.NewProtoCodec(registry), txtypes.DefaultSignModes)
+
+	base.AccountQuery.Km = NewKeyManager(store.NewPlaintextMemory(), "secp256k1")
+	address, _, err := base.AccountQuery.Km.Insert("alice", "12345678")
+	require.NoError(t, err)
+
+	send := func() error {
+		msg := &bank.MsgSend{FromAddress: address, ToAddress: address, Amount: sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1))}
+		_, err := base.BuildAndSend([]sdktypes.Msg{msg}, sdktypes.BaseTx{From: "alice", Password: "12345678"})
+		return err
+	}
+	require.NoError(t, send())
+	require.Equal(t, 1, node.broadcasts)
+
+	// another client sends a tx of the account in the same block, the cached sequence is behind and
+	// the account queried again after the wrong sequence is not served from the query cache
+	node.sequence++
+	require.NoError(t, send())
+	require.Equal(t, 3, node.broadcasts)
+	require.Equal(t, uint64(4), node.sequence)
+}
+
+func TestQueryCacheKinds(t *testing.T) {
+	node := &cacheNode{height: 10}
+	base := newCachedClient(node, time.Minute)
+
+	// the same path and request through Query and QueryStore are distinct entries
+	value, err := base.Query("/store/bank/key", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("10"), value)
+
+	res, err := base.QueryStore(nil, "bank", 0, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), res.Height)
+	require.Equal(t, 2, node.calls)
+
+	res, err = base.QueryStore(nil, "bank", 0, false)
+	require.NoError(t, err)
+	require.Equal(t, []byte("10"), res.Value)
+	require.Equal(t, 2, node.calls)
+}
@@ -20,6 +20,7 @@ type rpcClient struct {
 	log.Logger
 	cdc       *commoncodec.LegacyAmino
 	txDecoder sdk.TxDecoder
+	jsonRPC   sdkrpc.JSONRpcClient
 }
 
 func NewRPCClient(cfg sdktypes.ClientConfig,
@@ -38,6 +39,8 @@ func NewRPCClient(cfg sdktypes.ClientConfig,
 		panic(err)
 	}
 
+	client = client.WithBatchSize(cfg.RPCBatchSize)
+
 	if err := client.Start(); err != nil {
 		panic(err)
 	}
@@ -46,9 +49,15 @@ func NewRPCClient(cfg sdktypes.ClientConfig,
 		Logger:    logger,
 		cdc:       cdc,
 		txDecoder: txDecoder,
+		jsonRPC:   client,
 	}
 }
 
+// NewBatch implement BatchClient interface
+func (r rpcClient) NewBatch() *sdkrpc.Batch {
+	return r.jsonRPC.NewBatch()
+}
+
 // SubscribeNewBlock implement WSClient interface
 func (r rpcClient) SubscribeNewBlock(builder *sdk.EventQueryBuilder, handler sdk.EventNewBlockHandler) (sdk.Subscription, sdk.Error) {
 	if builder == nil {
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	kmg "github.com/irisnet/core-sdk-go/common/crypto"
+	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+// keyShareSecret is the secret split by ExportShares, the decrypted armor of the entry: a private
+// key or the mnemonic of an HD wallet
+type keyShareSecret struct {
+	Algo   string `json:"algo"`
+	Secret []byte `json:"secret"`
+}
+
+// ExportShares splits the secret of a key into count armored shares, any threshold of them
+// restore the key with ImportShares
+func (k KeyManager) ExportShares(name, password string, threshold, count int) ([]string, error) {
+	secret, err := k.readShareSecret(name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	return kmg.ArmorShares(bz, threshold, count)
+}
+
+// ImportShares restores a key exported by ExportShares, an HD wallet is restored with its accounts.
+// When the shares are too few the error is a crypto.InsufficientSharesError.
+func (k KeyManager) ImportShares(name, password string, shares []string) (string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", fmt.Errorf("%s has existed", name)
+	}
+
+	bz, err := kmg.CombineArmoredShares(shares)
+	if err != nil {
+		return "", err
+	}
+	var secret keyShareSecret
+	if err := json.Unmarshal(bz, &secret); err != nil {
+		return "", fmt.Errorf("invalid secret: %s", err.Error())
+	}
+
+	privKey, err := sharedPrivKey(secret)
+	if err != nil {
+		return "", err
+	}
+
+	pubKey := privKey.PubKey()
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: string(secret.Secret),
+		Algo:         secret.Algo,
+	}
+	if err := k.KeyDAO.Write(name, password, info); err != nil {
+		return "", err
+	}
+	return types.AccAddress(pubKey.Address().Bytes()).String(), nil
+}
+
+func (k KeyManager) readShareSecret(name, password string) (keyShareSecret, error) {
+	// an account of a wallet is exported as a plain key
+	if _, _, _, ok := parseHDAccountName(name); ok && !k.KeyDAO.Has(name) {
+		privKey, algo, err := k.readPrivKey(name, password)
+		if err != nil {
+			return keyShareSecret{}, fmt.Errorf("name %s not exist", name)
+		}
+		return keyShareSecret{Algo: algo, Secret: cryptoamino.MarshalPrivKey(privKey)}, nil
+	}
+
+	info, err := k.KeyDAO.Read(name, password)
+	if err != nil {
+		if _, ok := k.watchOnly(name); ok {
+			return keyShareSecret{}, watchOnlyError{name}
+		}
+		return keyShareSecret{}, fmt.Errorf("name %s not exist", name)
+	}
+	if info.IsWatchOnly() {
+		return keyShareSecret{}, watchOnlyError{name}
+	}
+
+	secret := keyShareSecret{Algo: info.Algo, Secret: []byte(info.PrivKeyArmor)}
+	// some KeyDAOs decrypt with any password, never split a wrongly decrypted secret
+	privKey, err := sharedPrivKey(secret)
+	if err != nil || !bytes.Equal(info.PubKey, cryptoamino.MarshalPubkey(privKey.PubKey())) {
+		return keyShareSecret{}, fmt.Errorf("wrong password")
+	}
+	return secret, nil
+}
+
+// sharedPrivKey returns the private key of the secret, the account 0/0 for an HD wallet
+func sharedPrivKey(secret keyShareSecret) (tmcrypto.PrivKey, error) {
+	if wallet, ok := store.ParseHDWalletSecret(string(secret.Secret)); ok {
+		return wallet.Derive(secret.Algo, 0, 0)
+	}
+	return cryptoamino.PrivKeyFromBytes(secret.Secret)
+}
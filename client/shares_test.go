@@ -0,0 +1,51 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestShares(t *testing.T) {
+	dao := store.NewMemory(store.AESGCM{ScryptLogN: 10})
+	km := NewKeyManager(dao, "secp256k1")
+
+	address, _, err := km.Insert("officer", "12345678")
+	require.NoError(t, err)
+	_, err = km.ExportShares("officer", "87654321", 2, 3)
+	require.Error(t, err)
+
+	shares, err := km.ExportShares("officer", "12345678", 2, 3)
+	require.NoError(t, err)
+	_, err = km.ImportShares("restored", "12345678", shares[:1])
+	require.EqualError(t, err, "1 of the 2 shares needed, 1 more required")
+
+	restored, err := km.ImportShares("restored", "12345678", shares[1:])
+	require.NoError(t, err)
+	require.Equal(t, address, restored)
+	signature, pubKey, err := km.Sign("restored", "12345678", []byte("data"))
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature([]byte("data"), signature))
+
+	// an HD wallet is restored with its accounts
+	_, _, err = km.CreateHDWallet("wallet", "12345678", testMnemonic, 118)
+	require.NoError(t, err)
+	accounts, err := km.DeriveHDAccounts("wallet", "12345678", 0, 1, 1)
+	require.NoError(t, err)
+	require.NoError(t, km.AddHDAccounts("wallet", "12345678", accounts...))
+
+	shares, err = km.ExportShares("wallet", "12345678", 3, 5)
+	require.NoError(t, err)
+	_, err = km.ImportShares("wallet2", "87654321", []string{shares[4], shares[0], shares[2]})
+	require.NoError(t, err)
+	recorded, err := km.HDAccounts("wallet2", "87654321")
+	require.NoError(t, err)
+	require.Equal(t, accounts[0].Address, recorded[1].Address)
+
+	_, err = km.ImportPubKey("watched", "12345678", pubKey)
+	require.NoError(t, err)
+	_, err = km.ExportShares("watched", "12345678", 2, 3)
+	require.EqualError(t, err, "watched is a watch-only key, it has no private key to sign with")
+}
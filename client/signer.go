@@ -0,0 +1,48 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/irisnet/core-sdk-go/types"
+)
+
+var errSignerManagesKeys = fmt.Errorf("the keys are managed by the signer")
+
+// signerKeyManager is the KeyManager of a client signing with a Signer, e.g. a remote signer.
+// Sign and Find go to the signer, the keys can't be managed by the client. The optional features
+// of the keys client, e.g. the listing of a KeyLister, are asserted on the signer.
+type signerKeyManager struct {
+	types.Signer
+}
+
+// newSignerKeyManager returns the signer itself when it manages its keys
+func newSignerKeyManager(signer types.Signer) types.KeyManager {
+	if keyManager, ok := signer.(types.KeyManager); ok {
+		return keyManager
+	}
+	return signerKeyManager{Signer: signer}
+}
+
+func (signerKeyManager) Insert(name, password string) (string, string, error) {
+	return "", "", errSignerManagesKeys
+}
+
+func (signerKeyManager) Recover(name, password, mnemonic, hdPath string) (string, error) {
+	return "", errSignerManagesKeys
+}
+
+func (signerKeyManager) Import(name, password string, privKeyArmor string) (string, error) {
+	return "", errSignerManagesKeys
+}
+
+func (signerKeyManager) Export(name, password string) (string, error) {
+	return "", errSignerManagesKeys
+}
+
+func (signerKeyManager) Delete(name, password string) error {
+	return errSignerManagesKeys
+}
+
+func (signerKeyManager) Add(name, password string) (string, string, types.Error) {
+	return "", "", types.Wrap(errSignerManagesKeys)
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestSignerKeyManager(t *testing.T) {
+	local := NewKeyManager(store.NewPlaintextMemory(), "secp256k1")
+	address, _, err := local.Insert("hot", "12345678")
+	require.NoError(t, err)
+
+	// only Sign and Find are forwarded to a signer
+	km := newSignerKeyManager(signerOnly{local})
+	_, addr, err := km.Find("hot", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+	_, _, err = km.Sign("hot", "12345678", []byte("data"))
+	require.NoError(t, err)
+
+	_, err = km.Export("hot", "12345678")
+	require.Equal(t, errSignerManagesKeys, err)
+	_, _, err = km.Insert("cold", "12345678")
+	require.Equal(t, errSignerManagesKeys, err)
+
+	// the optional features are asserted on the signer
+	client := keysClient{BIP44Params: *hd.NewFundraiserParams(0, 118, 0), KeyManager: km}
+	_, sdkErr := client.List()
+	require.Error(t, sdkErr)
+	_, sdkErr = client.ExportKeystore("hot", "12345678", "scrypt")
+	require.Error(t, sdkErr)
+
+	client.KeyManager = newSignerKeyManager(listerOnly{signerOnly{local}})
+	names, sdkErr := client.List()
+	require.NoError(t, sdkErr)
+	require.Equal(t, []string{"hot"}, names)
+
+	// a signer managing its keys is the key manager
+	require.Equal(t, local, newSignerKeyManager(local))
+}
+
+// signerOnly hides the methods of the KeyManager beyond the Signer
+type signerOnly struct {
+	km KeyManager
+}
+
+func (s signerOnly) Sign(name, password string, data []byte) ([]byte, tmcrypto.PubKey, error) {
+	return s.km.Sign(name, password, data)
+}
+
+func (s signerOnly) Find(name, password string) (tmcrypto.PubKey, types.AccAddress, error) {
+	return s.km.Find(name, password)
+}
+
+// listerOnly is a Signer and a KeyLister, like a remote signer
+type listerOnly struct {
+	signerOnly
+}
+
+func (l listerOnly) List() ([]string, error) {
+	return l.km.List()
+}
+
+func (l listerOnly) ListMetadata() ([]types.KeyMetadata, error) {
+	return l.km.ListMetadata()
+}
@@ -11,6 +11,7 @@ import (
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
 	sdk "github.com/irisnet/core-sdk-go/types"
+	sdkrpc "github.com/irisnet/core-sdk-go/types/rpc"
 	typetx "github.com/irisnet/core-sdk-go/types/tx"
 )
 
@@ -26,11 +27,11 @@ func (base baseClient) QueryTx(hash string) (sdk.ResultQueryTx, error) {
 		return sdk.ResultQueryTx{}, err
 	}
 
-	resBlocks, err := base.getResultBlocks([]*ctypes.ResultTx{res})
+	blockTimes, err := base.getBlockTimes([]*ctypes.ResultTx{res}, 1)
 	if err != nil {
 		return sdk.ResultQueryTx{}, err
 	}
-	return base.parseTxResult(res, resBlocks[res.Height])
+	return base.parseTxResult(res, blockTimes[res.Height])
 }
 
 func (base baseClient) QueryTxs(builder *sdk.EventQueryBuilder, page, size *int) (sdk.ResultSearchTxs, error) {
@@ -47,14 +48,14 @@ func (base baseClient) QueryTxs(builder *sdk.EventQueryBuilder, page, size *int)
 		return sdk.ResultSearchTxs{}, err
 	}
 
-	resBlocks, err := base.getResultBlocks(res.Txs)
+	blockTimes, err := base.getBlockTimes(res.Txs, blockFetchConcurrency)
 	if err != nil {
 		return sdk.ResultSearchTxs{}, err
 	}
 
 	var txs []sdk.ResultQueryTx
 	for i, tx := range res.Txs {
-		txInfo, err := base.parseTxResult(tx, resBlocks[res.Txs[i].Height])
+		txInfo, err := base.parseTxResult(tx, blockTimes[res.Txs[i].Height])
 		if err != nil {
 			return sdk.ResultSearchTxs{}, err
 		}
@@ -193,22 +194,94 @@ func (base baseClient) broadcastTxAsync(tx []byte) (sdk.ResultTx, sdk.Error) {
 	return sdk.ResultTx{Hash: res.Hash.String()}, nil
 }
 
-func (base baseClient) getResultBlocks(resTxs []*ctypes.ResultTx) (map[int64]*ctypes.ResultBlock, error) {
-	resBlocks := make(map[int64]*ctypes.ResultBlock)
+// getBlockTimes returns the block time of every height the txs were included at.
+// Blocks are fetched in json-rpc batches, at most `concurrency` batches in parallel, and their times are cached.
+func (base baseClient) getBlockTimes(resTxs []*ctypes.ResultTx, concurrency int) (map[int64]time.Time, error) {
+	blockTimes := make(map[int64]time.Time)
+
+	var heights []int64
 	for _, resTx := range resTxs {
-		if _, ok := resBlocks[resTx.Height]; !ok {
-			resBlock, err := base.Block(context.Background(), &resTx.Height)
-			if err != nil {
-				return nil, err
+		if _, ok := blockTimes[resTx.Height]; ok {
+			continue
+		}
+		if t, err := base.blockTimes.Get(resTx.Height); err == nil {
+			blockTimes[resTx.Height] = t.(time.Time)
+			continue
+		}
+		blockTimes[resTx.Height] = time.Time{}
+		heights = append(heights, resTx.Height)
+	}
+
+	if len(heights) == 0 {
+		return blockTimes, nil
+	}
+
+	batchSize := base.cfg.RPCBatchSize
+	if batchSize <= 0 {
+		batchSize = sdkrpc.DefaultBatchSize
+	}
+	var batches [][]int64
+	for start := 0; start < len(heights); start += batchSize {
+		end := start + batchSize
+		if end > len(heights) {
+			end = len(heights)
+		}
+		batches = append(batches, heights[start:end])
+	}
+
+	if concurrency <= 0 {
+		concurrency = blockFetchConcurrency
+	}
+	if concurrency > len(batches) {
+		concurrency = len(batches)
+	}
+
+	type result struct {
+		heights []int64
+		blocks  []interface{}
+		err     error
+	}
+
+	jobs := make(chan []int64, len(batches))
+	results := make(chan result, len(batches))
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			for batchHeights := range jobs {
+				batch := base.NewBatch()
+				for _, height := range batchHeights {
+					batch.Block(height)
+				}
+				blocks, err := batch.Send(context.Background())
+				results <- result{heights: batchHeights, blocks: blocks, err: err}
 			}
+		}()
+	}
 
-			resBlocks[resTx.Height] = resBlock
+	for _, batchHeights := range batches {
+		jobs <- batchHeights
+	}
+	close(jobs)
+
+	var err error
+	for range batches {
+		res := <-results
+		if res.err != nil {
+			err = res.err
+			continue
+		}
+		for i, height := range res.heights {
+			t := res.blocks[i].(*ctypes.ResultBlock).Block.Time
+			blockTimes[height] = t
+			_ = base.blockTimes.Set(height, t)
 		}
 	}
-	return resBlocks, nil
+	if err != nil {
+		return nil, err
+	}
+	return blockTimes, nil
 }
 
-func (base baseClient) parseTxResult(res *ctypes.ResultTx, resBlock *ctypes.ResultBlock) (sdk.ResultQueryTx, error) {
+func (base baseClient) parseTxResult(res *ctypes.ResultTx, blockTime time.Time) (sdk.ResultQueryTx, error) {
 	var tx sdk.Tx
 	var err error
 
@@ -225,6 +298,7 @@ func (base baseClient) parseTxResult(res *ctypes.ResultTx, resBlock *ctypes.Resu
 	return sdk.ResultQueryTx{
 		Hash:   res.Hash.String(),
 		Height: res.Height,
+		Index:  res.Index,
 		Tx:     *unwrappedTx,
 		Result: sdk.TxResult{
 			Code:      res.TxResult.Code,
@@ -233,7 +307,7 @@ func (base baseClient) parseTxResult(res *ctypes.ResultTx, resBlock *ctypes.Resu
 			GasUsed:   res.TxResult.GasUsed,
 			Events:    sdk.StringifyEvents(res.TxResult.Events),
 		},
-		Timestamp: resBlock.Block.Time.Format(time.RFC3339),
+		Timestamp: blockTime.Format(time.RFC3339),
 	}, nil
 }
 
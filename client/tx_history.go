@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+const (
+	defaultTxHistoryLimit = 20
+	maxTxHistoryLimit     = 100
+)
+
+// QueryTxHistory returns a page of the txs involving the address. The results of one tx search per
+// event key are merged, deduplicated by hash and ordered by height, and every entry carries the net
+// balance change of the address.
+func (base baseClient) QueryTxHistory(address string, req sdk.TxHistoryRequest) (sdk.TxHistory, error) {
+	if _, err := sdk.AccAddressFromBech32(address); err != nil {
+		return sdk.TxHistory{}, err
+	}
+
+	if req.Order == "" {
+		req.Order = sdk.TxSearchOrderDesc
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultTxHistoryLimit
+	}
+	if req.Limit > maxTxHistoryLimit {
+		req.Limit = maxTxHistoryLimit
+	}
+	if len(req.Events) == 0 {
+		req.Events = sdk.DefaultTxHistoryEvents
+	}
+
+	opts := sdk.TxSearchOptions{
+		Order:    req.Order,
+		PageSize: req.Limit,
+	}
+
+	var cursor *sdk.TxHistoryCursor
+	if len(req.Cursor) > 0 {
+		c, err := sdk.ParseTxHistoryCursor(req.Cursor)
+		if err != nil {
+			return sdk.TxHistory{}, err
+		}
+		cursor = &c
+		if req.Order == sdk.TxSearchOrderDesc {
+			opts.MaxHeight = c.Height
+		} else {
+			opts.MinHeight = c.Height
+		}
+	}
+
+	// pin every search to the same height, otherwise each iterator would pin its own
+	if req.Order == sdk.TxSearchOrderDesc && opts.MaxHeight == 0 {
+		status, err := base.Status(context.Background())
+		if err != nil {
+			return sdk.TxHistory{}, err
+		}
+		opts.MaxHeight = status.SyncInfo.LatestBlockHeight
+	}
+
+	merger := txHistoryMerger{
+		order:  req.Order,
+		cursor: cursor,
+	}
+	for _, key := range req.Events {
+		builder := sdk.NewEventQueryBuilder().AddCondition(
+			sdk.Cond(sdk.EventKey(key)).EQ(sdk.EventValue(address)),
+		)
+		it, err := base.IterateTxs(builder, opts)
+		if err != nil {
+			return sdk.TxHistory{}, err
+		}
+		if err := merger.add(it); err != nil {
+			return sdk.TxHistory{}, err
+		}
+	}
+
+	var history sdk.TxHistory
+	seen := make(map[string]bool)
+	for len(history.Entries) < req.Limit {
+		tx, ok, err := merger.next()
+		if err != nil {
+			return sdk.TxHistory{}, err
+		}
+		if !ok {
+			return history, nil
+		}
+		if seen[tx.Hash] {
+			continue
+		}
+		seen[tx.Hash] = true
+
+		entry, err := sdk.NewTxHistoryEntry(address, tx)
+		if err != nil {
+			return sdk.TxHistory{}, err
+		}
+		history.Entries = append(history.Entries, entry)
+	}
+
+	if merger.hasNext() {
+		last := history.Entries[len(history.Entries)-1]
+		history.NextCursor = sdk.TxHistoryCursor{Height: last.Height, Index: last.Index}.String()
+	}
+	return history, nil
+}
+
+// txHistoryMerger merges several ordered tx iterators into a single ordered stream
+type txHistoryMerger struct {
+	order  sdk.TxSearchOrder
+	cursor *sdk.TxHistoryCursor
+	iters  []sdk.TxIterator
+	heads  []*sdk.ResultQueryTx
+}
+
+func (m *txHistoryMerger) add(it sdk.TxIterator) error {
+	m.iters = append(m.iters, it)
+	m.heads = append(m.heads, nil)
+	return m.advance(len(m.iters) - 1)
+}
+
+// advance moves the i-th iterator to its next tx located after the cursor
+func (m *txHistoryMerger) advance(i int) error {
+	m.heads[i] = nil
+	for m.iters[i].Next() {
+		tx := m.iters[i].Value()
+		if m.cursor != nil && !m.cursor.Before(position(tx), m.order) {
+			continue
+		}
+		m.heads[i] = &tx
+		return nil
+	}
+	return m.iters[i].Err()
+}
+
+func (m *txHistoryMerger) next() (sdk.ResultQueryTx, bool, error) {
+	best := -1
+	for i, head := range m.heads {
+		if head == nil {
+			continue
+		}
+		if best < 0 || position(*head).Before(position(*m.heads[best]), m.order) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return sdk.ResultQueryTx{}, false, nil
+	}
+
+	tx := *m.heads[best]
+	pos := position(tx)
+	for i, head := range m.heads {
+		if head != nil && position(*head) == pos {
+			if err := m.advance(i); err != nil {
+				return sdk.ResultQueryTx{}, false, err
+			}
+		}
+	}
+	return tx, true, nil
+}
+
+func (m *txHistoryMerger) hasNext() bool {
+	for _, head := range m.heads {
+		if head != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func position(tx sdk.ResultQueryTx) sdk.TxHistoryCursor {
+	return sdk.TxHistoryCursor{Height: tx.Height, Index: tx.Index}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+const (
+	defaultTxSearchPageSize = 30
+	maxTxSearchPageSize     = 100
+	txHeightKey             = "tx.height"
+)
+
+// IterateTxs returns an iterator over the txs matching the query, pages are fetched on demand.
+// When searching in descending order without MaxHeight, the search is pinned to the latest height
+// at the time of the call so that newly committed txs do not shift the pages.
+func (base baseClient) IterateTxs(builder *sdk.EventQueryBuilder, opts sdk.TxSearchOptions) (sdk.TxIterator, error) {
+	if builder == nil || len(builder.Build()) == 0 {
+		return nil, errors.New("must declare at least one tag to search")
+	}
+
+	switch opts.Order {
+	case "":
+		opts.Order = sdk.TxSearchOrderAsc
+	case sdk.TxSearchOrderAsc, sdk.TxSearchOrderDesc:
+	default:
+		return nil, fmt.Errorf("invalid order %s, must be asc or desc", opts.Order)
+	}
+
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultTxSearchPageSize
+	}
+	if opts.PageSize > maxTxSearchPageSize {
+		opts.PageSize = maxTxSearchPageSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = blockFetchConcurrency
+	}
+	if opts.MinHeight < 0 || opts.MaxHeight < 0 || (opts.MaxHeight > 0 && opts.MinHeight > opts.MaxHeight) {
+		return nil, fmt.Errorf("invalid height range [%d, %d]", opts.MinHeight, opts.MaxHeight)
+	}
+
+	if opts.Order == sdk.TxSearchOrderDesc && opts.MaxHeight == 0 {
+		status, err := base.Status(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		opts.MaxHeight = status.SyncInfo.LatestBlockHeight
+	}
+
+	query := builder.Build()
+	if opts.MinHeight > 0 {
+		query = fmt.Sprintf("%s AND %s", query, sdk.Cond(txHeightKey).GTE(opts.MinHeight).String())
+	}
+	if opts.MaxHeight > 0 {
+		query = fmt.Sprintf("%s AND %s", query, sdk.Cond(txHeightKey).LTE(opts.MaxHeight).String())
+	}
+
+	return &txIterator{
+		base:  base,
+		query: query,
+		opts:  opts,
+	}, nil
+}
+
+type txIterator struct {
+	base  baseClient
+	query string
+	opts  sdk.TxSearchOptions
+
+	page    int
+	total   int
+	fetched int
+	emitted int
+	done    bool
+
+	buf []sdk.ResultQueryTx
+	cur sdk.ResultQueryTx
+	err error
+}
+
+func (it *txIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.opts.Limit > 0 && it.emitted >= it.opts.Limit {
+		return false
+	}
+
+	if len(it.buf) == 0 {
+		if it.done {
+			return false
+		}
+		if it.err = it.fetch(); it.err != nil || len(it.buf) == 0 {
+			return false
+		}
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	it.emitted++
+	return true
+}
+
+func (it *txIterator) Value() sdk.ResultQueryTx {
+	return it.cur
+}
+
+func (it *txIterator) Total() int {
+	return it.total
+}
+
+func (it *txIterator) Err() error {
+	return it.err
+}
+
+func (it *txIterator) fetch() error {
+	it.page++
+	page, size := it.page, it.opts.PageSize
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(it.base.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	res, err := it.base.TxSearch(ctx, it.query, false, &page, &size, string(it.opts.Order))
+	if err != nil {
+		return err
+	}
+
+	it.total = res.TotalCount
+	it.fetched += len(res.Txs)
+	if len(res.Txs) < size || it.fetched >= it.total {
+		it.done = true
+	}
+
+	blockTimes, err := it.base.getBlockTimes(res.Txs, it.opts.Concurrency)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range res.Txs {
+		txInfo, err := it.base.parseTxResult(tx, blockTimes[tx.Height])
+		if err != nil {
+			return err
+		}
+		it.buf = append(it.buf, txInfo)
+	}
+	return nil
+}
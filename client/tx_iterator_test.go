@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/log"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	commoncache "github.com/irisnet/core-sdk-go/common/cache"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+	sdkrpc "github.com/irisnet/core-sdk-go/types/rpc"
+)
+
+// blockNode answers the block calls of the batches, a block is timed at its height in seconds
+type blockNode struct {
+	mu        sync.Mutex
+	requested []int64
+	// failHeight fails the batches requesting it
+	failHeight int64
+}
+
+func (n *blockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var requests []types.RPCRequest
+	if err := json.Unmarshal(body, &requests); err != nil {
+		_ = json.NewEncoder(w).Encode(types.RPCParseError(err))
+		return
+	}
+
+	var responses []types.RPCResponse
+	for _, req := range requests {
+		var params map[string]string
+		_ = json.Unmarshal(req.Params, &params)
+		height, _ := strconv.ParseInt(params["height"], 10, 64)
+
+		n.mu.Lock()
+		n.requested = append(n.requested, height)
+		n.mu.Unlock()
+		if height == n.failHeight {
+			responses = append(responses, types.RPCInternalError(req.ID, errors.New("block not available")))
+			continue
+		}
+
+		bz, _ := tmjson.Marshal(&ctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{
+			Height: height,
+			Time:   blockTime(height),
+		}}})
+		responses = append(responses, types.RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: bz})
+	}
+	_ = json.NewEncoder(w).Encode(responses)
+}
+
+func (n *blockNode) requests() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.requested)
+}
+
+func blockTime(height int64) time.Time {
+	return time.Unix(height, 0).UTC()
+}
+
+// txNode pages through its txs and fetches the block times from a blockNode
+type txNode struct {
+	sdktypes.TmClient
+	rpc    sdkrpc.JSONRpcClient
+	blocks *blockNode
+	txs    []*ctypes.ResultTx
+	latest int64
+
+	queries   []string
+	pages     []int
+	searchErr error
+}
+
+func (n *txNode) NewBatch() *sdkrpc.Batch {
+	return n.rpc.NewBatch()
+}
+
+func (n *txNode) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: n.latest}}, nil
+}
+
+func (n *txNode) TxSearch(_ context.Context, query string, _ bool, page, perPage *int, _ string) (*ctypes.ResultTxSearch, error) {
+	n.queries = append(n.queries, query)
+	n.pages = append(n.pages, *page)
+	if n.searchErr != nil {
+		return nil, n.searchErr
+	}
+
+	start := (*page - 1) * *perPage
+	end := start + *perPage
+	if start > len(n.txs) {
+		start = len(n.txs)
+	}
+	if end > len(n.txs) {
+		end = len(n.txs)
+	}
+	return &ctypes.ResultTxSearch{Txs: n.txs[start:end], TotalCount: len(n.txs)}, nil
+}
+
+// newTxClient returns a client whose txs are at the heights and whose block batches hold at most
+// batchSize calls
+func newTxClient(t *testing.T, batchSize int, heights ...int64) (*baseClient, *txNode) {
+	blocks := &blockNode{}
+	server := httptest.NewServer(blocks)
+	t.Cleanup(server.Close)
+	rpc, err := sdkrpc.NewJSONRpcClient(server.URL, "", "/websocket", 5, nil)
+	require.NoError(t, err)
+
+	encodingConfig := newTxEncodingConfig()
+	from := sdktypes.AccAddress([]byte("from________________"))
+	node := &txNode{rpc: rpc.WithBatchSize(batchSize), blocks: blocks}
+	for i, height := range heights {
+		node.txs = append(node.txs, &ctypes.ResultTx{
+			Height: height,
+			Index:  uint32(i),
+			Tx:     encodeSend(t, encodingConfig.TxConfig, from, from, strconv.Itoa(i)),
+		})
+	}
+
+	base := &baseClient{
+		TmClient:       node,
+		cfg:            &sdktypes.ClientConfig{Timeout: 5, RPCBatchSize: batchSize},
+		encodingConfig: encodingConfig,
+		blockTimes:     commoncache.NewCache(blockTimeCacheCapacity, true),
+		AccountQuery:   AccountQuery{Logger: log.NewNopLogger()},
+	}
+	return base, node
+}
+
+func TestGetBlockTimes(t *testing.T) {
+	base, node := newTxClient(t, 2, 1, 1, 2, 3, 4, 5)
+
+	// the heights are fetched once, by batches of the batch size spread over the workers
+	blockTimes, err := base.getBlockTimes(node.txs, 2)
+	require.NoError(t, err)
+	require.Len(t, blockTimes, 5)
+	for height, tm := range blockTimes {
+		require.Equal(t, blockTime(height), tm)
+	}
+	require.ElementsMatch(t, []int64{1, 2, 3, 4, 5}, node.blocks.requested)
+
+	// the cached heights aren't fetched again
+	blockTimes, err = base.getBlockTimes(node.txs[:4], 2)
+	require.NoError(t, err)
+	require.Len(t, blockTimes, 3)
+	require.Equal(t, 5, node.blocks.requests())
+
+	more := []*ctypes.ResultTx{{Height: 5}, {Height: 6}}
+	blockTimes, err = base.getBlockTimes(more, 2)
+	require.NoError(t, err)
+	require.Equal(t, blockTime(6), blockTimes[6])
+	require.Equal(t, []int64{1, 2, 3, 4, 5, 6}, sortedHeights(node.blocks.requested))
+}
+
+func TestGetBlockTimesError(t *testing.T) {
+	base, node := newTxClient(t, 2, 1, 2, 3, 4, 5)
+	node.blocks.failHeight = 3
+
+	_, err := base.getBlockTimes(node.txs, 3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "block not available")
+
+	// the times of the other batches are kept, the failed batch is fetched again
+	requests := node.blocks.requests()
+	node.blocks.failHeight = 0
+	blockTimes, err := base.getBlockTimes(node.txs, 3)
+	require.NoError(t, err)
+	require.Len(t, blockTimes, 5)
+	require.Equal(t, requests+2, node.blocks.requests())
+}
+
+func TestTxIterator(t *testing.T) {
+	base, node := newTxClient(t, 20, 1, 2, 2, 3, 5)
+	builder := sdktypes.NewEventQueryBuilder().AddCondition(sdktypes.Cond("message.action").EQ("send"))
+
+	// the pages are fetched on demand, the last one is short
+	it, err := base.IterateTxs(builder, sdktypes.TxSearchOptions{PageSize: 2})
+	require.NoError(t, err)
+	var memos []string
+	for it.Next() {
+		memos = append(memos, it.Value().Tx.Body.Memo)
+		require.Equal(t, blockTime(it.Value().Height).Format(time.RFC3339), it.Value().Timestamp)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"0", "1", "2", "3", "4"}, memos)
+	require.Equal(t, 5, it.Total())
+	require.Equal(t, []int{1, 2, 3}, node.pages)
+
+	// a full last page ends the iteration without fetching an empty page
+	node.pages = nil
+	it, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{PageSize: 5})
+	require.NoError(t, err)
+	for it.Next() {
+	}
+	require.Equal(t, []int{1}, node.pages)
+
+	// the limit stops the iteration before the next page
+	node.pages = nil
+	it, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{PageSize: 2, Limit: 2})
+	require.NoError(t, err)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+	require.Equal(t, []int{1}, node.pages)
+
+	// a descending search is pinned to the latest height
+	node.latest = 5
+	node.queries = nil
+	it, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{Order: sdktypes.TxSearchOrderDesc, MinHeight: 2})
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	require.Equal(t, "message.action='send' AND tx.height>=2 AND tx.height<=5", node.queries[0])
+
+	// an error ends the iteration
+	node.searchErr = errors.New("connection refused")
+	it, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{})
+	require.NoError(t, err)
+	require.False(t, it.Next())
+	require.Equal(t, node.searchErr, it.Err())
+
+	_, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{Order: "random"})
+	require.Error(t, err)
+	_, err = base.IterateTxs(builder, sdktypes.TxSearchOptions{MinHeight: 5, MaxHeight: 2})
+	require.Error(t, err)
+	_, err = base.IterateTxs(sdktypes.NewEventQueryBuilder(), sdktypes.TxSearchOptions{})
+	require.Error(t, err)
+}
+
+func sortedHeights(heights []int64) []int64 {
+	sorted := append([]int64(nil), heights...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+// watchOnlyError is returned when a watch-only key is asked for its private key
+type watchOnlyError struct {
+	name string
+}
+
+func (e watchOnlyError) Error() string {
+	return fmt.Sprintf("%s is a watch-only key, it has no private key to sign with", e.name)
+}
+
+func isWatchOnlyError(err error) bool {
+	_, ok := err.(watchOnlyError)
+	return ok
+}
+
+// ImportPubKey stores a watch-only key holding the public key of an account managed elsewhere.
+// The password only protects the entry against deletion and renaming, it is checked against the
+// encrypted store.WatchOnlyVerifier.
+func (k KeyManager) ImportPubKey(name, password string, pubKey tmcrypto.PubKey) (string, error) {
+	if k.KeyDAO.Has(name) {
+		return "", fmt.Errorf("%s has existed", name)
+	}
+
+	algo, err := pubKeyAlgo(pubKey)
+	if err != nil {
+		return "", err
+	}
+
+	info := store.KeyInfo{
+		Name:         name,
+		PubKey:       cryptoamino.MarshalPubkey(pubKey),
+		PrivKeyArmor: store.WatchOnlyVerifier,
+		Algo:         algo,
+		Type:         store.TypeOffline,
+	}
+	if err := k.KeyDAO.Write(name, password, info); err != nil {
+		return "", err
+	}
+	return types.AccAddress(pubKey.Address().Bytes()).String(), nil
+}
+
+// ImportAddress stores a watch-only key known only by its address, the public key of such a key
+// is unknown until its account signed a tx
+func (k KeyManager) ImportAddress(name, password, address string) error {
+	if k.KeyDAO.Has(name) {
+		return fmt.Errorf("%s has existed", name)
+	}
+
+	accAddress, err := types.AccAddressFromBech32(address)
+	if err != nil {
+		return err
+	}
+
+	info := store.KeyInfo{
+		Name:         name,
+		PrivKeyArmor: store.WatchOnlyVerifier,
+		Algo:         k.Algo,
+		Type:         store.TypeOffline,
+		Address:      tmcrypto.Address(accAddress),
+	}
+	return k.KeyDAO.Write(name, password, info)
+}
+
+// watchOnly returns the public information of a watch-only key, it is read without the password
+func (k KeyManager) watchOnly(name string) (store.KeyMetadata, bool) {
+	if !k.KeyDAO.Has(name) {
+		return store.KeyMetadata{}, false
+	}
+
+	if reader, ok := k.KeyDAO.(store.MetadataReader); ok {
+		info, err := reader.ReadMetadata(name)
+		if err != nil {
+			return store.KeyMetadata{}, false
+		}
+		info.Name = name
+		return info.Metadata(), info.IsWatchOnly()
+	}
+
+	metadata, err := k.KeyDAO.ListMetadata()
+	if err != nil {
+		return store.KeyMetadata{}, false
+	}
+	for _, m := range metadata {
+		if m.Name == name {
+			return m, m.Type == store.TypeOffline
+		}
+	}
+	return store.KeyMetadata{}, false
+}
+
+// findWatchOnly returns the public key, nil when only the address is known, and the address of
+// a watch-only key
+func findWatchOnly(algo string, pubKeyBz []byte, address tmcrypto.Address) (tmcrypto.PubKey, types.AccAddress, error) {
+	if len(pubKeyBz) == 0 {
+		return nil, types.AccAddress(address), nil
+	}
+
+	pubKey, err := cryptoamino.PubKeyFromBytes(pubKeyBz)
+	if err != nil {
+		return nil, nil, err
+	}
+	return FromTmPubKey(algo, pubKey), types.AccAddress(pubKey.Address().Bytes()), nil
+}
+
+// pubKeyAlgo returns the algo of the public keys handled by FromTmPubKey
+func pubKeyAlgo(pubKey tmcrypto.PubKey) (string, error) {
+	switch pubKey.(type) {
+	case *secp256k1.PubKey:
+		return "secp256k1", nil
+	case *sm2.PubKey:
+		return "sm2", nil
+	case *ethsecp256k1.PubKey:
+		return ethsecp256k1.KeyType, nil
+	case *ed25519.PubKey:
+		return string(hd.Ed25519Type), nil
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", pubKey)
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+	"github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+func TestWatchOnly(t *testing.T) {
+	levelDB, err := store.NewLevelDB(t.TempDir(), store.AES{})
+	require.NoError(t, err)
+
+	daos := map[string]store.KeyDAO{
+		"file":             store.NewFileDAO(t.TempDir()),
+		"leveldb":          levelDB,
+		"memory":           store.NewPlaintextMemory(),
+		"encrypted memory": store.NewMemory(store.AESGCM{ScryptLogN: 10}),
+	}
+	for kind, dao := range daos {
+		t.Run(kind, func(t *testing.T) {
+			// a failed read looks up the one key, not the whole store
+			_, ok := dao.(store.MetadataReader)
+			require.True(t, ok)
+			km := NewKeyManager(dao, "secp256k1")
+
+			pubKey := secp256k1.GenPrivKey().PubKey()
+			address, err := km.ImportPubKey("watched", "12345678", pubKey)
+			require.NoError(t, err)
+			_, err = km.ImportPubKey("watched", "12345678", pubKey)
+			require.Error(t, err)
+
+			other := types.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+			require.Error(t, km.ImportAddress("address", "12345678", "iaa1invalid"))
+			require.NoError(t, km.ImportAddress("address", "12345678", other))
+
+			// the public information is found without the password
+			for _, password := range []string{"12345678", ""} {
+				found, addr, err := km.Find("watched", password)
+				require.NoError(t, err)
+				require.Equal(t, address, addr.String())
+				require.True(t, pubKey.Equals(found))
+
+				found, addr, err = km.Find("address", password)
+				require.NoError(t, err)
+				require.Equal(t, other, addr.String())
+				require.Nil(t, found)
+			}
+
+			_, _, err = km.Sign("watched", "12345678", []byte("data"))
+			require.EqualError(t, err, "watched is a watch-only key, it has no private key to sign with")
+			_, _, err = km.Sign("address", "", []byte("data"))
+			require.EqualError(t, err, "address is a watch-only key, it has no private key to sign with")
+			_, err = km.Export("watched", "12345678")
+			require.Error(t, err)
+
+			metadata, err := km.ListMetadata()
+			require.NoError(t, err)
+			require.Len(t, metadata, 2)
+			require.Equal(t, other, metadata[0].Address)
+			require.True(t, metadata[0].WatchOnly)
+			require.Equal(t, address, metadata[1].Address)
+			require.True(t, metadata[1].WatchOnly)
+
+			// the password guards the entries
+			for _, password := range []string{"87654321", ""} {
+				require.Error(t, km.Rename("address", "renamed", password))
+				require.Error(t, km.Delete("watched", password))
+				require.True(t, dao.Has("address"))
+				require.True(t, dao.Has("watched"))
+			}
+
+			require.NoError(t, km.Rename("address", "renamed", "12345678"))
+			_, addr, err := km.Find("renamed", "")
+			require.NoError(t, err)
+			require.Equal(t, other, addr.String())
+			require.NoError(t, km.Delete("watched", "12345678"))
+		})
+	}
+}
+
+// unlistedDAO fails the listing of the keys
+type unlistedDAO struct {
+	store.MemoryDAO
+}
+
+func (unlistedDAO) ListMetadata() ([]store.KeyMetadata, error) {
+	return nil, errors.New("the keys are listed")
+}
+
+func TestWatchOnlyReadsOneKey(t *testing.T) {
+	km := NewKeyManager(unlistedDAO{store.NewPlaintextMemory()}, "secp256k1")
+	_, _, err := km.Insert("local", "12345678")
+	require.NoError(t, err)
+	pubKey := secp256k1.GenPrivKey().PubKey()
+	address, err := km.ImportPubKey("watched", "12345678", pubKey)
+	require.NoError(t, err)
+
+	_, addr, err := km.Find("watched", "")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+
+	_, _, err = km.Sign("local", "87654321", []byte("data"))
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "listed")
+}
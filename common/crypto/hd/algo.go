@@ -8,9 +8,10 @@ import (
 	ethcrypto"github.com/ethereum/go-ethereum/crypto"
 	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
 
-	bip39 "github.com/cosmos/go-bip39"
 	"github.com/tendermint/tendermint/crypto"
+	goed25519 "golang.org/x/crypto/ed25519"
 
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/ed25519"
 	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
 	"github.com/irisnet/core-sdk-go/common/crypto/keys/sm2"
 )
@@ -18,6 +19,7 @@ import (
 type SignatureAlgo interface {
 	Name() PubKeyType
 	Derive() DeriveFn
+	DeriveFromSeed() DeriveFromSeedFn
 	Generate() GenerateFn
 }
 
@@ -29,6 +31,8 @@ func NewSigningAlgoFromString(str string) (SignatureAlgo, error) {
 		return Sm2, nil
 	case string(EthSecp256k1.Name()):
 		return EthSecp256k1, nil
+	case string(Ed25519.Name()):
+		return Ed25519, nil
 	default:
 		return nil, fmt.Errorf("provided algorithm `%s` is not supported", str)
 	}
@@ -42,8 +46,7 @@ const (
 	MultiType = PubKeyType("multi")
 	// Secp256k1Type uses the Bitcoin secp256k1 ECDSA parameters.
 	Secp256k1Type = PubKeyType("secp256k1")
-	// Ed25519Type represents the Ed25519Type signature system.
-	// It is currently not supported for end-user keys (wallets/ledgers).
+	// Ed25519Type represents the Ed25519Type signature system, its keys are derived with SLIP-10.
 	Ed25519Type = PubKeyType("ed25519")
 	// Sr25519Type represents the Sr25519Type signature system.
 	Sr25519Type = PubKeyType("sr25519")
@@ -61,9 +64,13 @@ var (
 	Sm2 = sm2Algo{}
 
 	EthSecp256k1 = ethSecp256k1Algo{}
+
+	// Ed25519 derives its keys with SLIP-10, only hardened indices are supported
+	Ed25519 = ed25519Algo{}
 )
 
 type DeriveFn func(mnemonic string, bip39Passphrase, hdPath string) ([]byte, error)
+type DeriveFromSeedFn func(seed []byte, hdPath string) ([]byte, error)
 type GenerateFn func(bz []byte) crypto.PrivKey
 
 type WalletGenerator interface {
@@ -71,6 +78,17 @@ type WalletGenerator interface {
 	Generate(bz []byte) crypto.PrivKey
 }
 
+// deriveFromMnemonic validates the mnemonic against the registered wordlists before deriving from its seed
+func deriveFromMnemonic(deriveFromSeed DeriveFromSeedFn) DeriveFn {
+	return func(mnemonic string, bip39Passphrase, hdPath string) ([]byte, error) {
+		seed, err := NewSeed(mnemonic, bip39Passphrase, nil)
+		if err != nil {
+			return nil, err
+		}
+		return deriveFromSeed(seed, hdPath)
+	}
+}
+
 type secp256k1Algo struct {
 }
 
@@ -78,14 +96,14 @@ func (s secp256k1Algo) Name() PubKeyType {
 	return Secp256k1Type
 }
 
-// Derive derives and returns the secp256k1 private key for the given seed and HD path.
+// Derive derives and returns the secp256k1 private key for the given mnemonic and HD path.
 func (s secp256k1Algo) Derive() DeriveFn {
-	return func(mnemonic string, bip39Passphrase, hdPath string) ([]byte, error) {
-		seed, err := bip39.NewSeedWithErrorChecking(mnemonic, bip39Passphrase)
-		if err != nil {
-			return nil, err
-		}
+	return deriveFromMnemonic(s.DeriveFromSeed())
+}
 
+// DeriveFromSeed derives and returns the secp256k1 private key for the given seed and HD path.
+func (s secp256k1Algo) DeriveFromSeed() DeriveFromSeedFn {
+	return func(seed []byte, hdPath string) ([]byte, error) {
 		masterPriv, ch := ComputeMastersFromSeed(seed)
 		if len(hdPath) == 0 {
 			return masterPriv[:], nil
@@ -112,14 +130,14 @@ func (s sm2Algo) Name() PubKeyType {
 	return Sm2Type
 }
 
-// Derive derives and returns the secp256k1 private key for the given seed and HD path.
+// Derive derives and returns the sm2 private key for the given mnemonic and HD path.
 func (s sm2Algo) Derive() DeriveFn {
-	return func(mnemonic string, bip39Passphrase, hdPath string) ([]byte, error) {
-		seed, err := bip39.NewSeedWithErrorChecking(mnemonic, bip39Passphrase)
-		if err != nil {
-			return nil, err
-		}
+	return deriveFromMnemonic(s.DeriveFromSeed())
+}
 
+// DeriveFromSeed derives and returns the sm2 private key for the given seed and HD path.
+func (s sm2Algo) DeriveFromSeed() DeriveFromSeedFn {
+	return func(seed []byte, hdPath string) ([]byte, error) {
 		masterPriv, ch := ComputeMastersFromSeed(seed)
 		if len(hdPath) == 0 {
 			return masterPriv[:], nil
@@ -147,13 +165,13 @@ func (s ethSecp256k1Algo) Name() PubKeyType {
 
 // Derive derives and returns the eth_secp256k1 private key for the given mnemonic and HD path.
 func (s ethSecp256k1Algo) Derive() DeriveFn {
-	return func(mnemonic, bip39Passphrase, path string) ([]byte, error) {
-		hdpath, err := accounts.ParseDerivationPath(path)
-		if err != nil {
-			return nil, err
-		}
+	return deriveFromMnemonic(s.DeriveFromSeed())
+}
 
-		seed, err := bip39.NewSeedWithErrorChecking(mnemonic, bip39Passphrase)
+// DeriveFromSeed derives and returns the eth_secp256k1 private key for the given seed and HD path.
+func (s ethSecp256k1Algo) DeriveFromSeed() DeriveFromSeedFn {
+	return func(seed []byte, path string) ([]byte, error) {
+		hdpath, err := accounts.ParseDerivationPath(path)
 		if err != nil {
 			return nil, err
 		}
@@ -196,3 +214,29 @@ func (s ethSecp256k1Algo) Generate() GenerateFn {
 		return &ethsecp256k1.PrivKey{Key: bzArr}
 	}
 }
+
+type ed25519Algo struct{}
+
+// Name returns ed25519
+func (s ed25519Algo) Name() PubKeyType {
+	return Ed25519Type
+}
+
+// Derive derives and returns the ed25519 private key seed for the given mnemonic and HD path.
+func (s ed25519Algo) Derive() DeriveFn {
+	return deriveFromMnemonic(s.DeriveFromSeed())
+}
+
+// DeriveFromSeed derives and returns the ed25519 private key seed for the given seed and SLIP-10 path.
+func (s ed25519Algo) DeriveFromSeed() DeriveFromSeedFn {
+	return DeriveSLIP10Ed25519
+}
+
+// Generate generates an ed25519 private key from the given seed.
+func (s ed25519Algo) Generate() GenerateFn {
+	return func(bz []byte) crypto.PrivKey {
+		seed := make([]byte, ed25519.SeedSize)
+		copy(seed, bz)
+		return &ed25519.PrivKey{Key: goed25519.NewKeyFromSeed(seed)}
+	}
+}
@@ -0,0 +1,249 @@
+package hd
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	bip39 "github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// LanguageEnglish is the language of the default wordlist
+	LanguageEnglish = "english"
+	// LanguageJapanese mnemonics are joined with ideographic spaces
+	LanguageJapanese = "japanese"
+
+	// DefaultWordCount is the length of the generated mnemonics
+	DefaultWordCount = 24
+
+	wordlistSize = 2048
+	bitsPerWord  = 11
+)
+
+// English is the BIP39 English wordlist
+var English = mustNewWordlist(LanguageEnglish, bip39.EnglishWordList)
+
+var (
+	wordlistsMtx sync.RWMutex
+	wordlists    = map[string]*Wordlist{LanguageEnglish: English}
+)
+
+// Wordlist is a BIP39 wordlist, the words are kept NFKD normalized
+type Wordlist struct {
+	language string
+	words    []string
+	index    map[string]int
+}
+
+// NewWordlist validates a wordlist of the BIP39 repository, e.g. the lines of japanese.txt
+func NewWordlist(language string, words []string) (*Wordlist, error) {
+	if len(words) != wordlistSize {
+		return nil, fmt.Errorf("a wordlist must have %d words, got %d", wordlistSize, len(words))
+	}
+
+	wl := &Wordlist{
+		language: strings.ToLower(language),
+		words:    make([]string, wordlistSize),
+		index:    make(map[string]int, wordlistSize),
+	}
+	for i, word := range words {
+		word = norm.NFKD.String(strings.TrimSpace(word))
+		if len(word) == 0 || strings.ContainsAny(word, " \t") {
+			return nil, fmt.Errorf("invalid word %q at line %d", word, i+1)
+		}
+		if _, ok := wl.index[word]; ok {
+			return nil, fmt.Errorf("duplicate word %q", word)
+		}
+		wl.words[i] = word
+		wl.index[word] = i
+	}
+	return wl, nil
+}
+
+func mustNewWordlist(language string, words []string) *Wordlist {
+	wl, err := NewWordlist(language, words)
+	if err != nil {
+		panic(err)
+	}
+	return wl
+}
+
+// Language returns the lower case language of the wordlist
+func (wl *Wordlist) Language() string {
+	return wl.language
+}
+
+// RegisterWordlist makes a wordlist available to WordlistByLanguage and to the detection of the
+// language of a mnemonic. Only English is built in, other languages are registered from the
+// wordlists of the BIP39 repository.
+func RegisterWordlist(wl *Wordlist) {
+	wordlistsMtx.Lock()
+	defer wordlistsMtx.Unlock()
+	wordlists[wl.language] = wl
+}
+
+// WordlistByLanguage returns a registered wordlist, English when the language is empty
+func WordlistByLanguage(language string) (*Wordlist, error) {
+	if len(language) == 0 {
+		return English, nil
+	}
+
+	wordlistsMtx.RLock()
+	defer wordlistsMtx.RUnlock()
+	wl, ok := wordlists[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("wordlist %s is not registered", language)
+	}
+	return wl, nil
+}
+
+// NewMnemonic generates a mnemonic of 12, 15, 18, 21 or 24 words, English is used when the
+// wordlist is nil
+func NewMnemonic(wordCount int, wordlist *Wordlist) (string, error) {
+	if err := validateWordCount(wordCount); err != nil {
+		return "", err
+	}
+	if wordlist == nil {
+		wordlist = English
+	}
+
+	entropy := make([]byte, wordCount/3*4)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+
+	bits := append(entropy, sha256.Sum256(entropy)[0])
+	words := make([]string, wordCount)
+	for i := range words {
+		words[i] = wordlist.words[readBits(bits, i*bitsPerWord)]
+	}
+
+	separator := " "
+	if wordlist.language == LanguageJapanese {
+		separator = "　"
+	}
+	return strings.Join(words, separator), nil
+}
+
+// ValidateMnemonic checks the length, the words and the checksum of the mnemonic. When the
+// wordlist is nil the language is detected among the registered wordlists.
+func ValidateMnemonic(mnemonic string, wordlist *Wordlist) error {
+	_, err := parseMnemonic(mnemonic, wordlist)
+	return err
+}
+
+// NewSeed returns the BIP39 seed of a valid mnemonic protected by the passphrase, the "25th word"
+func NewSeed(mnemonic, passphrase string, wordlist *Wordlist) ([]byte, error) {
+	words, err := parseMnemonic(mnemonic, wordlist)
+	if err != nil {
+		return nil, err
+	}
+
+	sentence := strings.Join(words, " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(sentence), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+// parseMnemonic returns the NFKD normalized words of a valid mnemonic
+func parseMnemonic(mnemonic string, wordlist *Wordlist) ([]string, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if err := validateWordCount(len(words)); err != nil {
+		return nil, err
+	}
+
+	if wordlist == nil {
+		var err error
+		if wordlist, err = detectWordlist(words); err != nil {
+			return nil, err
+		}
+	}
+
+	bits := make([]byte, (len(words)*bitsPerWord+7)/8)
+	for i, word := range words {
+		index, ok := wordlist.index[word]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic: word %d %q is not in the %s wordlist", i+1, word, wordlist.language)
+		}
+		writeBits(bits, i*bitsPerWord, index)
+	}
+
+	entropyLen := len(words) / 3 * 4
+	checksumBits := uint(entropyLen / 4)
+	checksum := sha256.Sum256(bits[:entropyLen])
+	if bits[entropyLen]>>(8-checksumBits) != checksum[0]>>(8-checksumBits) {
+		return nil, fmt.Errorf("invalid mnemonic: checksum mismatch, check the order and the spelling of the words")
+	}
+	return words, nil
+}
+
+// detectWordlist returns the registered wordlist holding all the words, English first
+func detectWordlist(words []string) (*Wordlist, error) {
+	wordlistsMtx.RLock()
+	candidates := make([]*Wordlist, 0, len(wordlists))
+	for _, wl := range wordlists {
+		candidates = append(candidates, wl)
+	}
+	wordlistsMtx.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i] == English || candidates[j] == English {
+			return candidates[i] == English
+		}
+		return candidates[i].language < candidates[j].language
+	})
+
+	for _, wl := range candidates {
+		found := true
+		for _, word := range words {
+			if _, ok := wl.index[word]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return wl, nil
+		}
+	}
+
+	// report the first unknown word in English
+	for i, word := range words {
+		if _, ok := English.index[word]; !ok {
+			return nil, fmt.Errorf("invalid mnemonic: word %d %q is not in any registered wordlist", i+1, word)
+		}
+	}
+	return English, nil
+}
+
+func validateWordCount(wordCount int) error {
+	if wordCount < 12 || wordCount > 24 || wordCount%3 != 0 {
+		return fmt.Errorf("invalid mnemonic: it must have 12, 15, 18, 21 or 24 words, got %d", wordCount)
+	}
+	return nil
+}
+
+// readBits reads the 11 bits starting at offset
+func readBits(bits []byte, offset int) int {
+	value := 0
+	for i := 0; i < bitsPerWord; i++ {
+		bit := offset + i
+		value = value<<1 | int(bits[bit/8]>>(7-uint(bit%8))&1)
+	}
+	return value
+}
+
+// writeBits writes the 11 bits of value starting at offset
+func writeBits(bits []byte, offset, value int) {
+	for i := 0; i < bitsPerWord; i++ {
+		if value>>(bitsPerWord-1-i)&1 == 1 {
+			bit := offset + i
+			bits[bit/8] |= 1 << (7 - uint(bit%8))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package hd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// slip10Ed25519Curve is the HMAC key of the ed25519 master key
+const slip10Ed25519Curve = "ed25519 seed"
+
+// DeriveSLIP10Ed25519 derives the 32 bytes ed25519 seed of the SLIP-10 path. ed25519 only supports
+// hardened derivation, the indices without apostrophe are hardened too, e.g. m/44'/118'/0'/0/0
+// derives m/44'/118'/0'/0'/0'.
+func DeriveSLIP10Ed25519(seed []byte, path string) ([]byte, error) {
+	key, chainCode := i64([]byte(slip10Ed25519Curve), seed)
+
+	indices, err := parseSLIP10Path(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		data := append([]byte{0}, key[:]...)
+		data = append(data, uint32ToBytes(index|0x80000000)...)
+		key, chainCode = i64(chainCode[:], data)
+	}
+
+	derivedKey := make([]byte, 32)
+	copy(derivedKey, key[:])
+	return derivedKey, nil
+}
+
+// parseSLIP10Path returns the indices of an absolute path, an empty path is the master key
+func parseSLIP10Path(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	if len(path) == 0 || path == "m" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "m/") {
+		return nil, fmt.Errorf("invalid SLIP-10 path %s: use the 'm/' prefix", path)
+	}
+
+	parts := strings.Split(path[2:], "/")
+	indices := make([]uint32, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSuffix(strings.TrimSuffix(part, "'"), "H")
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLIP-10 path %s: %s", path, err.Error())
+		}
+		indices[i] = uint32(index)
+	}
+	return indices, nil
+}
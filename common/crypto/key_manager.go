@@ -1,9 +1,6 @@
 package crypto
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/pkg/errors"
 
 	"github.com/tendermint/tendermint/crypto"
@@ -49,6 +46,45 @@ func NewAlgoKeyManager(algo string) (KeyManager, error) {
 	return NewMnemonicKeyManager(mnemonic, algo)
 }
 
+// MnemonicOptions customize the generation and the recovery of a mnemonic
+type MnemonicOptions struct {
+	// BIP39Passphrase is the optional "25th word"
+	BIP39Passphrase string
+	// WordCount of a generated mnemonic, 12, 15, 18, 21 or 24, defaults to 24
+	WordCount int
+	// Wordlist of a generated mnemonic defaults to English, a recovered mnemonic is checked against
+	// all the registered wordlists when it is nil
+	Wordlist *hd.Wordlist
+	// HDPath defaults to hd.FullPath
+	HDPath string
+}
+
+// NewAlgoKeyManagerWithOptions generates a mnemonic of the given size and derives its key
+func NewAlgoKeyManagerWithOptions(algo string, opts MnemonicOptions) (KeyManager, error) {
+	if opts.WordCount == 0 {
+		opts.WordCount = hd.DefaultWordCount
+	}
+	mnemonic, err := hd.NewMnemonic(opts.WordCount, opts.Wordlist)
+	if err != nil {
+		return nil, err
+	}
+	return NewMnemonicKeyManagerWithOptions(mnemonic, algo, opts)
+}
+
+// NewMnemonicKeyManagerWithOptions recovers a key protected by a BIP39 passphrase or written with
+// a non-English wordlist
+func NewMnemonicKeyManagerWithOptions(mnemonic, algo string, opts MnemonicOptions) (KeyManager, error) {
+	if len(opts.HDPath) == 0 {
+		opts.HDPath = hd.FullPath
+	}
+	k := keyManager{
+		mnemonic: mnemonic,
+		algo:     algo,
+	}
+	err := k.recover(mnemonic, opts.BIP39Passphrase, opts.HDPath, algo, opts.Wordlist)
+	return &k, err
+}
+
 func NewMnemonicKeyManager(mnemonic string, algo string) (KeyManager, error) {
 	k := keyManager{
 		mnemonic: mnemonic,
@@ -88,18 +124,22 @@ func (m *keyManager) Sign(data []byte) ([]byte, error) {
 }
 
 func (m *keyManager) recoveryFromMnemonic(mnemonic, hdPath, algoStr string) error {
-	words := strings.Split(mnemonic, " ")
-	if len(words) != 12 && len(words) != 24 {
-		return fmt.Errorf("mnemonic length should either be 12 or 24")
-	}
+	return m.recover(mnemonic, defaultBIP39Passphrase, hdPath, algoStr, nil)
+}
 
+func (m *keyManager) recover(mnemonic, bip39Passphrase, hdPath, algoStr string, wordlist *hd.Wordlist) error {
 	algo, err := hd.NewSigningAlgoFromString(algoStr)
 	if err != nil {
 		return err
 	}
 
+	seed, err := hd.NewSeed(mnemonic, bip39Passphrase, wordlist)
+	if err != nil {
+		return err
+	}
+
 	// create master key and derive first key for keyring
-	derivedPriv, err := algo.Derive()(mnemonic, defaultBIP39Passphrase, hdPath)
+	derivedPriv, err := algo.DeriveFromSeed()(seed, hdPath)
 	if err != nil {
 		return err
 	}
@@ -1,11 +1,15 @@
 package crypto_test
 
 import (
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/irisnet/core-sdk-go/common/crypto"
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
 	sdk "github.com/irisnet/core-sdk-go/types"
 )
 
@@ -23,3 +27,100 @@ func TestNewMnemonicKeyManager(t *testing.T) {
 	address := sdk.AccAddress(pubKey.Address()).String()
 	assert.Equal(t, "iaa1y9kd9uy7a4qnjp0z5yjx5jhrkv2ycdkzqc0h8z", address)
 }
+
+func TestMnemonicOptions(t *testing.T) {
+	// BIP39 test vector
+	seed, err := hd.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	for _, wordCount := range []int{12, 15, 18, 21, 24} {
+		km, err := crypto.NewAlgoKeyManagerWithOptions("secp256k1", crypto.MnemonicOptions{WordCount: wordCount, BIP39Passphrase: "25th"})
+		assert.NoError(t, err)
+		mnemonic, priv := km.Generate()
+		assert.Len(t, strings.Fields(mnemonic), wordCount)
+		assert.NoError(t, hd.ValidateMnemonic(mnemonic, nil))
+
+		recovered, err := crypto.NewMnemonicKeyManagerWithOptions(mnemonic, "secp256k1", crypto.MnemonicOptions{BIP39Passphrase: "25th"})
+		assert.NoError(t, err)
+		_, recoveredPriv := recovered.Generate()
+		assert.True(t, priv.Equals(recoveredPriv))
+
+		// the passphrase changes the key
+		withoutPassphrase, err := crypto.NewMnemonicKeyManager(mnemonic, "secp256k1")
+		assert.NoError(t, err)
+		_, otherPriv := withoutPassphrase.Generate()
+		assert.False(t, priv.Equals(otherPriv))
+	}
+
+	_, err = crypto.NewAlgoKeyManagerWithOptions("secp256k1", crypto.MnemonicOptions{WordCount: 13})
+	assert.EqualError(t, err, "invalid mnemonic: it must have 12, 15, 18, 21 or 24 words, got 13")
+
+	err = hd.ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", nil)
+	assert.EqualError(t, err, "invalid mnemonic: checksum mismatch, check the order and the spelling of the words")
+
+	err = hd.ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abot", nil)
+	assert.EqualError(t, err, `invalid mnemonic: word 12 "abot" is not in any registered wordlist`)
+}
+
+func TestRegisterWordlist(t *testing.T) {
+	words := make([]string, 2048)
+	for i := range words {
+		words[i] = fmt.Sprintf("mot%04d", i)
+	}
+	_, err := hd.NewWordlist("test", words[1:])
+	assert.Error(t, err)
+
+	wordlist, err := hd.NewWordlist("test", words)
+	assert.NoError(t, err)
+	hd.RegisterWordlist(wordlist)
+
+	registered, err := hd.WordlistByLanguage("TEST")
+	assert.NoError(t, err)
+	mnemonic, err := hd.NewMnemonic(12, registered)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(mnemonic, "mot"))
+
+	// the language is detected on recovery
+	km, err := crypto.NewMnemonicKeyManagerWithOptions(mnemonic, "secp256k1", crypto.MnemonicOptions{})
+	assert.NoError(t, err)
+	assert.NotNil(t, km.ExportPubKey())
+
+	_, err = hd.NewSeed(mnemonic, "", hd.English)
+	assert.Error(t, err)
+}
+
+func TestEd25519KeyManager(t *testing.T) {
+	// SLIP-10 test vector 1 for ed25519
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	for path, key := range map[string]string{
+		"m":                         "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		"m/0'":                      "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		"m/0'/1'/2'/2'/1000000000'": "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+	} {
+		derived, err := hd.DeriveSLIP10Ed25519(seed, path)
+		assert.NoError(t, err)
+		assert.Equal(t, key, hex.EncodeToString(derived), path)
+	}
+	_, err := hd.DeriveSLIP10Ed25519(seed, "44'/118'")
+	assert.Error(t, err)
+
+	// the indices of the default path are hardened
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	km, err := crypto.NewMnemonicKeyManager(mnemonic, "ed25519")
+	assert.NoError(t, err)
+	hardened, err := crypto.NewMnemonicKeyManagerWithHDPath(mnemonic, "ed25519", "m/44'/118'/0'/0'/0'")
+	assert.NoError(t, err)
+	assert.Equal(t, hardened.ExportPubKey(), km.ExportPubKey())
+
+	signature, err := km.Sign([]byte("data"))
+	assert.NoError(t, err)
+	assert.True(t, km.ExportPubKey().VerifySignature([]byte("data"), signature))
+
+	armor, err := km.ExportPrivKey("12345678")
+	assert.NoError(t, err)
+	priv, algo, err := crypto.NewKeyManager().ImportPrivKey(armor, "12345678")
+	assert.NoError(t, err)
+	assert.Equal(t, "ed25519", algo)
+	assert.Equal(t, km.ExportPubKey(), priv.PubKey())
+}
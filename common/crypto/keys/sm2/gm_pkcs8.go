@@ -0,0 +1,308 @@
+package sm2
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+	"github.com/tjfoc/gmsm/sm4"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	pemTypePrivateKey          = "PRIVATE KEY"
+	pemTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemTypePublicKey           = "PUBLIC KEY"
+
+	sm4KeySize = 16
+	saltSize   = 16
+	// maxPBKDF2Iterations bounds the work an imported key can ask for
+	maxPBKDF2Iterations = 1 << 24
+)
+
+// PBKDF2Iterations is the iteration count of the exported private keys
+var PBKDF2Iterations = 65536
+
+var (
+	oidPBES2   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidSHA1    = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidSHA256  = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACSM3 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401, 2}
+	oidSM4CBC  = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 2}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 EncryptedPrivateKeyInfo with PBES2 parameters (RFC 8018)
+type encryptedPrivateKeyInfo struct {
+	Algo          pbes2Algorithm
+	EncryptedData []byte
+}
+
+type pbes2Algorithm struct {
+	Algorithm asn1.ObjectIdentifier
+	Params    pbes2Params
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pbkdf2Algorithm
+	EncryptionScheme  encryptionScheme
+}
+
+type pbkdf2Algorithm struct {
+	Algorithm asn1.ObjectIdentifier
+	Params    pbkdf2Params
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type encryptionScheme struct {
+	Algorithm asn1.ObjectIdentifier
+	IV        []byte
+}
+
+// MarshalPKCS8PrivateKey exports the key as an ENCRYPTED PRIVATE KEY PEM: PKCS#8 encrypted with
+// PBES2, PBKDF2-HMAC-SM3 and SM4-CBC, the layout used by GmSSL
+func MarshalPKCS8PrivateKey(privKey PrivKey, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("a password is required to export a private key")
+	}
+
+	der, err := gmx509.MarshalSm2UnecryptedPrivateKey(privKey.GetPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	salt := crypto.CRandBytes(saltSize)
+	iv := crypto.CRandBytes(sm4.BlockSize)
+	key := pbkdf2.Key(password, salt, PBKDF2Iterations, sm4KeySize, sm3.New)
+
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padded := pkcs7Pad(der, sm4.BlockSize)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	info := encryptedPrivateKeyInfo{
+		Algo: pbes2Algorithm{
+			Algorithm: oidPBES2,
+			Params: pbes2Params{
+				KeyDerivationFunc: pbkdf2Algorithm{
+					Algorithm: oidPBKDF2,
+					Params: pbkdf2Params{
+						Salt:           salt,
+						IterationCount: PBKDF2Iterations,
+						KeyLength:      sm4KeySize,
+						PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACSM3, Parameters: asn1.NullRawValue},
+					},
+				},
+				EncryptionScheme: encryptionScheme{Algorithm: oidSM4CBC, IV: iv},
+			},
+		},
+		EncryptedData: encrypted,
+	}
+	bz, err := asn1.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: bz}), nil
+}
+
+// ParsePKCS8PrivateKey imports a PKCS#8 PEM private key. Encrypted keys may use SM4-CBC or AES-CBC
+// with PBKDF2 over HMAC-SM3, HMAC-SHA1 or HMAC-SHA256.
+func ParsePKCS8PrivateKey(pemBytes, password []byte) (PrivKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return PrivKey{}, fmt.Errorf("no PEM block found")
+	}
+
+	var (
+		priv *sm2.PrivateKey
+		err  error
+	)
+	switch block.Type {
+	case pemTypePrivateKey:
+		priv, err = gmx509.ParsePKCS8UnecryptedPrivateKey(block.Bytes)
+	case pemTypeEncryptedPrivateKey:
+		priv, err = parseEncryptedPKCS8(block.Bytes, password)
+	default:
+		return PrivKey{}, fmt.Errorf("unsupported PEM type %s", block.Type)
+	}
+	if err != nil {
+		return PrivKey{}, err
+	}
+	return fromPrivateKey(priv), nil
+}
+
+// MarshalPublicKey exports the key as a PUBLIC KEY PEM holding its SubjectPublicKeyInfo
+func MarshalPublicKey(pubKey PubKey) ([]byte, error) {
+	der, err := gmx509.MarshalSm2PublicKey(pubKey.GetPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: der}), nil
+}
+
+// ParsePublicKey imports a PUBLIC KEY PEM holding a SubjectPublicKeyInfo
+func ParsePublicKey(pemBytes []byte) (PubKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != pemTypePublicKey {
+		return PubKey{}, fmt.Errorf("no %s PEM block found", pemTypePublicKey)
+	}
+
+	pub, err := gmx509.ParseSm2PublicKey(block.Bytes)
+	if err != nil {
+		return PubKey{}, err
+	}
+	if pub.X == nil || pub.Y == nil {
+		return PubKey{}, fmt.Errorf("the public key is not on the SM2 curve")
+	}
+	return PubKey{Key: sm2.Compress(pub)}, nil
+}
+
+// GetPublicKey returns the decompressed public key
+func (pubKey PubKey) GetPublicKey() *sm2.PublicKey {
+	return sm2.Decompress(pubKey.Key)
+}
+
+func parseEncryptedPKCS8(der, password []byte) (*sm2.PrivateKey, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key: %s", err.Error())
+	}
+
+	if !info.Algo.Algorithm.Equal(oidPBES2) || !info.Algo.Params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("only PBES2 with PBKDF2 is supported")
+	}
+	params := info.Algo.Params.KeyDerivationFunc.Params
+	if params.IterationCount <= 0 || params.IterationCount > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("invalid iteration count %d", params.IterationCount)
+	}
+
+	scheme := info.Algo.Params.EncryptionScheme
+	if !scheme.Algorithm.Equal(oidSM4CBC) {
+		// AES-CBC, as written by OpenSSL and gmsm
+		return gmx509.ParsePKCS8EcryptedPrivateKey(der, password)
+	}
+	if params.KeyLength != 0 && params.KeyLength != sm4KeySize {
+		return nil, fmt.Errorf("invalid key length %d", params.KeyLength)
+	}
+	if len(scheme.IV) != sm4.BlockSize || len(info.EncryptedData) == 0 || len(info.EncryptedData)%sm4.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid encrypted data")
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case params.PRF.Algorithm == nil, params.PRF.Algorithm.Equal(oidSHA1):
+		prf = sha1.New
+	case params.PRF.Algorithm.Equal(oidSHA256):
+		prf = sha256.New
+	case params.PRF.Algorithm.Equal(oidHMACSM3):
+		prf = sm3.New
+	default:
+		return nil, fmt.Errorf("unsupported prf %s", params.PRF.Algorithm)
+	}
+
+	block, err := sm4.NewCipher(pbkdf2.Key(password, params.Salt, params.IterationCount, sm4KeySize, prf))
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, scheme.IV).CryptBlocks(decrypted, info.EncryptedData)
+
+	unpadded, ok := pkcs7Unpad(decrypted, sm4.BlockSize)
+	if !ok {
+		return nil, fmt.Errorf("wrong password")
+	}
+	priv, err := gmx509.ParsePKCS8UnecryptedPrivateKey(unpadded)
+	if err != nil {
+		return nil, fmt.Errorf("wrong password")
+	}
+	return priv, nil
+}
+
+func fromPrivateKey(priv *sm2.PrivateKey) PrivKey {
+	key := make([]byte, PrivKeySize)
+	d := priv.D.Bytes()
+	copy(key[PrivKeySize-len(d):], d)
+	return PrivKey{Key: key}
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, bool) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, false
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, false
+		}
+	}
+	return data[:len(data)-padding], true
+}
+
+// VerifyGMSignature verifies a signature produced by GM/T 0003 tooling: the message is hashed
+// with SM3 over Z_A || msg where Z_A is derived from the user ID, the default
+// "1234567812345678" is used when uid is empty. The signature is either DER encoded, as written
+// by GmSSL and gmsm, or the raw r || s used by the SDK.
+func VerifyGMSignature(pubKey PubKey, msg, sig, uid []byte) bool {
+	r, s, ok := parseSignature(sig)
+	if !ok {
+		return false
+	}
+	return sm2.Sm2Verify(pubKey.GetPublicKey(), msg, uid, r, s)
+}
+
+// VerifyGMDigest verifies a signature over a digest e = SM3(Z_A || msg) computed by the caller,
+// as done by hardware devices that only receive the digest
+func VerifyGMDigest(pubKey PubKey, digest, sig []byte) bool {
+	r, s, ok := parseSignature(sig)
+	if !ok {
+		return false
+	}
+	return sm2.Verify(pubKey.GetPublicKey(), digest, r, s)
+}
+
+// SM3Digest returns e = SM3(Z_A || msg), the digest actually signed for msg
+func SM3Digest(pubKey PubKey, msg, uid []byte) ([]byte, error) {
+	return pubKey.GetPublicKey().Sm3Digest(msg, uid)
+}
+
+func parseSignature(sig []byte) (r, s *big.Int, ok bool) {
+	// a DER signature starts with a SEQUENCE tag and is 64 bytes long only when r and s are short
+	if len(sig) > 0 && sig[0] == 0x30 {
+		var der struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(sig, &der); err == nil && len(rest) == 0 && der.R != nil && der.S != nil {
+			return der.R, der.S, true
+		}
+	}
+
+	if len(sig) != SignatureSize {
+		return nil, nil, false
+	}
+	return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), true
+}
@@ -0,0 +1,95 @@
+package sm2
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+func TestPKCS8PrivateKey(t *testing.T) {
+	PBKDF2Iterations = 1024
+	privKey := GenPrivKey()
+
+	bz, err := MarshalPKCS8PrivateKey(privKey, []byte("12345678"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(bz)
+	require.Equal(t, "ENCRYPTED PRIVATE KEY", block.Type)
+
+	parsed, err := ParsePKCS8PrivateKey(bz, []byte("12345678"))
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(parsed))
+
+	_, err = ParsePKCS8PrivateKey(bz, []byte("87654321"))
+	require.Error(t, err)
+	_, err = MarshalPKCS8PrivateKey(privKey, nil)
+	require.Error(t, err)
+
+	// keys encrypted with AES by other tools
+	der, err := gmx509.MarshalSm2EcryptedPrivateKey(privKey.GetPrivateKey(), []byte("12345678"))
+	require.NoError(t, err)
+	parsed, err = ParsePKCS8PrivateKey(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), []byte("12345678"))
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(parsed))
+
+	der, err = gmx509.MarshalSm2UnecryptedPrivateKey(privKey.GetPrivateKey())
+	require.NoError(t, err)
+	parsed, err = ParsePKCS8PrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(parsed))
+}
+
+func TestPublicKey(t *testing.T) {
+	pubKey := GenPrivKey().PubKey().(*PubKey)
+
+	bz, err := MarshalPublicKey(*pubKey)
+	require.NoError(t, err)
+	parsed, err := ParsePublicKey(bz)
+	require.NoError(t, err)
+	require.True(t, pubKey.Equals(&parsed))
+
+	_, err = ParsePublicKey([]byte("-----BEGIN PUBLIC KEY-----\nMAA=\n-----END PUBLIC KEY-----\n"))
+	require.Error(t, err)
+}
+
+func TestVerifyGMSignature(t *testing.T) {
+	privKey := GenPrivKey()
+	pubKey := *privKey.PubKey().(*PubKey)
+	msg := []byte("message")
+
+	// DER signature with the default user ID, as written by GM tools
+	der, err := privKey.GetPrivateKey().Sign(rand.Reader, msg, nil)
+	require.NoError(t, err)
+	require.True(t, VerifyGMSignature(pubKey, msg, der, nil))
+	require.False(t, VerifyGMSignature(pubKey, []byte("other"), der, nil))
+
+	// raw signature of the SDK
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, VerifyGMSignature(pubKey, msg, sig, nil))
+
+	// custom user ID
+	uid := []byte("alice@example.com")
+	r, s, err := sm2.Sm2Sign(privKey.GetPrivateKey(), msg, uid, rand.Reader)
+	require.NoError(t, err)
+	der, err = asn1Signature(r, s)
+	require.NoError(t, err)
+	require.True(t, VerifyGMSignature(pubKey, msg, der, uid))
+	require.False(t, VerifyGMSignature(pubKey, msg, der, nil))
+
+	digest, err := SM3Digest(pubKey, msg, uid)
+	require.NoError(t, err)
+	require.True(t, VerifyGMDigest(pubKey, digest, der))
+	require.False(t, VerifyGMDigest(pubKey, msg, der))
+
+	require.False(t, VerifyGMSignature(pubKey, msg, []byte("invalid"), nil))
+}
+
+func asn1Signature(r, s *big.Int) ([]byte, error) {
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
@@ -0,0 +1,235 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/tendermint/tendermint/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+)
+
+// Key derivation functions of the Web3 Secret Storage keystores
+const (
+	KeystoreKDFScrypt = "scrypt"
+	KeystoreKDFPBKDF2 = "pbkdf2"
+
+	keystoreVersion = 3
+	keystoreCipher  = "aes-128-ctr"
+	keystoreDKLen   = 32
+	keystorePRF     = "hmac-sha256"
+
+	// the bounds of the work and memory an imported keystore can ask for, scrypt uses 128*N*r bytes
+	maxKeystoreScryptLogN       = 20
+	maxKeystoreScryptRP         = 32
+	maxKeystoreScryptMemory     = 256 << 20
+	maxKeystorePBKDF2Iterations = 1 << 22
+)
+
+// KeystoreScryptN, KeystoreScryptP and KeystorePBKDF2Iterations are the parameters of the exported
+// keystores, the defaults are the ones of geth and MetaMask
+var (
+	KeystoreScryptN          = 1 << 18
+	KeystoreScryptP          = 1
+	KeystorePBKDF2Iterations = 262144
+)
+
+type keystoreJSON struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string `json:"cipher"`
+	CipherText   string `json:"ciphertext"`
+	CipherParams struct {
+		IV string `json:"iv"`
+	} `json:"cipherparams"`
+	KDF       string         `json:"kdf"`
+	KDFParams keystoreParams `json:"kdfparams"`
+	MAC       string         `json:"mac"`
+}
+
+// keystoreParams holds the parameters of both KDFs, N, R and P for scrypt, C and PRF for pbkdf2
+type keystoreParams struct {
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+	N     int    `json:"n,omitempty"`
+	R     int    `json:"r,omitempty"`
+	P     int    `json:"p,omitempty"`
+	C     int    `json:"c,omitempty"`
+	PRF   string `json:"prf,omitempty"`
+}
+
+// EncryptKeystore exports an eth_secp256k1 private key as a Web3 Secret Storage V3 keystore
+// readable by geth and MetaMask, kdf defaults to scrypt
+func EncryptKeystore(privKey crypto.PrivKey, passphrase, kdf string) (string, error) {
+	ethPrivKey, ok := privKey.(*ethsecp256k1.PrivKey)
+	if !ok {
+		return "", fmt.Errorf("only %s keys can be exported as keystore", ethsecp256k1.KeyType)
+	}
+
+	params := keystoreParams{
+		DKLen: keystoreDKLen,
+		Salt:  hex.EncodeToString(crypto.CRandBytes(32)),
+	}
+	switch kdf {
+	case "", KeystoreKDFScrypt:
+		kdf = KeystoreKDFScrypt
+		params.N, params.R, params.P = KeystoreScryptN, 8, KeystoreScryptP
+	case KeystoreKDFPBKDF2:
+		params.C, params.PRF = KeystorePBKDF2Iterations, keystorePRF
+	default:
+		return "", fmt.Errorf("unsupported kdf %s", kdf)
+	}
+
+	derivedKey, err := deriveKeystoreKey(kdf, params, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	iv := crypto.CRandBytes(aes.BlockSize)
+	cipherText, err := aesCTR(derivedKey[:16], iv, ethPrivKey.Key)
+	if err != nil {
+		return "", err
+	}
+
+	ks := keystoreJSON{
+		Address: hex.EncodeToString(privKey.PubKey().Address()),
+		ID:      newUUID(),
+		Version: keystoreVersion,
+		Crypto: keystoreCrypto{
+			Cipher:     keystoreCipher,
+			CipherText: hex.EncodeToString(cipherText),
+			KDF:        kdf,
+			KDFParams:  params,
+			MAC:        hex.EncodeToString(keystoreMAC(derivedKey, cipherText)),
+		},
+	}
+	ks.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+
+	bz, err := json.Marshal(ks)
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+// DecryptKeystore imports the eth_secp256k1 private key of a Web3 Secret Storage V3 keystore
+// encrypted with scrypt or pbkdf2
+func DecryptKeystore(keystore, passphrase string) (crypto.PrivKey, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal([]byte(keystore), &ks); err != nil {
+		return nil, fmt.Errorf("invalid keystore: %s", err.Error())
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported cipher %s", ks.Crypto.Cipher)
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %s", err.Error())
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv")
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac: %s", err.Error())
+	}
+
+	derivedKey, err := deriveKeystoreKey(ks.Crypto.KDF, ks.Crypto.KDFParams, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(keystoreMAC(derivedKey, cipherText), mac) != 1 {
+		return nil, fmt.Errorf("wrong password")
+	}
+
+	key, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ethcrypto.ToECDSA(key); err != nil {
+		return nil, fmt.Errorf("invalid private key: %s", err.Error())
+	}
+
+	privKey := &ethsecp256k1.PrivKey{Key: key}
+	if len(ks.Address) > 0 {
+		address, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(ks.Address), "0x"))
+		if err != nil || !bytes.Equal(address, privKey.PubKey().Address()) {
+			return nil, fmt.Errorf("the keystore address does not match its key")
+		}
+	}
+	return privKey, nil
+}
+
+func deriveKeystoreKey(kdf string, params keystoreParams, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %s", err.Error())
+	}
+	if params.DKLen < keystoreDKLen {
+		return nil, fmt.Errorf("invalid dklen %d", params.DKLen)
+	}
+
+	switch kdf {
+	case KeystoreKDFScrypt:
+		// N is a power of 2 up to 2^maxKeystoreScryptLogN
+		if params.N <= 1 || params.N > 1<<maxKeystoreScryptLogN || params.N&(params.N-1) != 0 ||
+			params.R <= 0 || params.P <= 0 || params.R*params.P > maxKeystoreScryptRP ||
+			128*int64(params.N)*int64(params.R) > maxKeystoreScryptMemory {
+			return nil, fmt.Errorf("invalid scrypt parameters")
+		}
+		return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	case KeystoreKDFPBKDF2:
+		if params.PRF != keystorePRF {
+			return nil, fmt.Errorf("unsupported prf %s", params.PRF)
+		}
+		if params.C <= 0 || params.C > maxKeystorePBKDF2Iterations {
+			return nil, fmt.Errorf("invalid pbkdf2 parameters")
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf %s", kdf)
+	}
+}
+
+// keystoreMAC is keccak256(derivedKey[16:32] || ciphertext)
+func keystoreMAC(derivedKey, cipherText []byte) []byte {
+	return ethcrypto.Keccak256(derivedKey[16:32], cipherText)
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	u := crypto.CRandBytes(16)
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
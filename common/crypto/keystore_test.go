@@ -0,0 +1,81 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/common/crypto"
+	ethsecp256k1 "github.com/irisnet/core-sdk-go/common/crypto/keys/eth_secp256k1"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+)
+
+// test vectors of the Web3 Secret Storage definition
+const (
+	keystorePBKDF2 = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	keystoreScrypt = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+
+	keystoreKey     = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	keystoreAddress = "008aeeda4d805471df9b2a5b0f38a0c3bcba786b"
+)
+
+func TestDecryptKeystore(t *testing.T) {
+	for _, keystore := range []string{keystorePBKDF2, keystoreScrypt} {
+		privKey, err := crypto.DecryptKeystore(keystore, "testpassword")
+		require.NoError(t, err)
+		require.Equal(t, keystoreKey, hex.EncodeToString(privKey.Bytes()))
+		require.Equal(t, keystoreAddress, hex.EncodeToString(privKey.PubKey().Address()))
+
+		_, err = crypto.DecryptKeystore(keystore, "wrongpassword")
+		require.Error(t, err)
+	}
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	scryptN, iterations := crypto.KeystoreScryptN, crypto.KeystorePBKDF2Iterations
+	t.Cleanup(func() {
+		crypto.KeystoreScryptN, crypto.KeystorePBKDF2Iterations = scryptN, iterations
+	})
+	crypto.KeystoreScryptN, crypto.KeystorePBKDF2Iterations = 1<<10, 1024
+
+	privKey, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+
+	for _, kdf := range []string{crypto.KeystoreKDFScrypt, crypto.KeystoreKDFPBKDF2} {
+		keystore, err := crypto.EncryptKeystore(privKey, "12345678", kdf)
+		require.NoError(t, err)
+		require.Contains(t, keystore, hex.EncodeToString(privKey.PubKey().Address()))
+
+		decrypted, err := crypto.DecryptKeystore(keystore, "12345678")
+		require.NoError(t, err)
+		require.True(t, privKey.Equals(decrypted))
+	}
+
+	_, err = crypto.EncryptKeystore(privKey, "12345678", "bcrypt")
+	require.Error(t, err)
+	_, err = crypto.EncryptKeystore(secp256k1.GenPrivKey(), "12345678", "")
+	require.Error(t, err)
+}
+
+func TestDecryptKeystoreBounds(t *testing.T) {
+	withParams := func(from, to string) string {
+		require.Contains(t, keystoreScrypt, from)
+		return strings.Replace(keystoreScrypt, from, to, 1)
+	}
+
+	for name, keystore := range map[string]string{
+		// 2 GiB of memory for less work than the bound of the test vector
+		"memory":   withParams(`"n":262144,"r":1,"p":8`, `"n":16777216,"r":1,"p":1`),
+		"logN":     withParams(`"n":262144`, `"n":2097152`),
+		"power":    withParams(`"n":262144`, `"n":262143`),
+		"rp":       withParams(`"r":1,"p":8`, `"r":1,"p":64`),
+		"memoryR":  withParams(`"r":1,"p":8`, `"r":16,"p":1`),
+		"pbkdf2 c": strings.Replace(keystorePBKDF2, `"c":262144`, `"c":16777216`, 1),
+	} {
+		_, err := crypto.DecryptKeystore(keystore, "testpassword")
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "parameters", name)
+	}
+}
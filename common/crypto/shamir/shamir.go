@@ -0,0 +1,127 @@
+// Package shamir implements Shamir's secret sharing over GF(256), the field of AES
+package shamir
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// MaxShares is the number of distinct non-zero x coordinates of GF(256)
+const MaxShares = 255
+
+var (
+	expTable [510]byte
+	logTable [256]byte
+)
+
+func init() {
+	// 3 generates the multiplicative group of GF(256) with the AES polynomial x^8+x^4+x^3+x+1
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		expTable[i+255] = x
+		logTable[x] = byte(i)
+		x ^= xtime(x)
+	}
+}
+
+// xtime multiplies by x modulo the AES polynomial
+func xtime(b byte) byte {
+	if b&0x80 != 0 {
+		return b<<1 ^ 0x1b
+	}
+	return b << 1
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
+
+// Share is the evaluation at X of the polynomials hiding every byte of the secret
+type Share struct {
+	X     byte
+	Value []byte
+}
+
+// Split splits the secret into n shares, any threshold of them recover it
+func Split(secret []byte, threshold, n int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("empty secret")
+	}
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("the threshold must be between 1 and the number of shares, got %d of %d", threshold, n)
+	}
+	if n > MaxShares {
+		return nil, fmt.Errorf("at most %d shares, got %d", MaxShares, n)
+	}
+
+	// coefficients[i] holds the random coefficients of degree 1 to threshold-1 of the byte i
+	coefficients := make([]byte, len(secret)*(threshold-1))
+	if _, err := rand.Read(coefficients); err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, n)
+	for s := range shares {
+		x := byte(s + 1)
+		value := make([]byte, len(secret))
+		for i, b := range secret {
+			// Horner's rule from the highest degree
+			y := byte(0)
+			for d := threshold - 2; d >= 0; d-- {
+				y = mul(y, x) ^ coefficients[i*(threshold-1)+d]
+			}
+			value[i] = mul(y, x) ^ b
+		}
+		shares[s] = Share{X: x, Value: value}
+	}
+	return shares, nil
+}
+
+// Combine interpolates the secret at 0, the shares must be distinct and at least the threshold of
+// the split, otherwise a wrong secret is returned
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no share")
+	}
+
+	size := len(shares[0].Value)
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if share.X == 0 {
+			return nil, fmt.Errorf("invalid share index 0")
+		}
+		if seen[share.X] {
+			return nil, fmt.Errorf("duplicate share %d", share.X)
+		}
+		if len(share.Value) != size {
+			return nil, fmt.Errorf("the shares have different lengths")
+		}
+		seen[share.X] = true
+	}
+
+	secret := make([]byte, size)
+	for i, share := range shares {
+		// Lagrange basis polynomial of the share evaluated at 0
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = mul(basis, div(other.X, other.X^share.X))
+			}
+		}
+		for k, b := range share.Value {
+			secret[k] ^= mul(b, basis)
+		}
+	}
+	return secret, nil
+}
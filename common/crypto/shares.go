@@ -0,0 +1,163 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/tendermint/tendermint/crypto/armor"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/shamir"
+)
+
+const (
+	blockTypeShare = "TENDERMINT SECRET SHARE"
+
+	headerShareID        = "id"
+	headerShareIndex     = "index"
+	headerShareThreshold = "threshold"
+
+	shareVersion = 1
+	// version, id, threshold, index and the digest of the secret
+	shareHeaderLen   = 1 + 2 + 1 + 1 + 4
+	shareChecksumLen = 4
+)
+
+// InsufficientSharesError is returned by CombineArmoredShares when less shares than the threshold are given
+type InsufficientSharesError struct {
+	Threshold int
+	Have      int
+}
+
+func (e InsufficientSharesError) Error() string {
+	return fmt.Sprintf("%d of the %d shares needed, %d more required", e.Have, e.Threshold, e.Threshold-e.Have)
+}
+
+// Needed returns how many more shares are required
+func (e InsufficientSharesError) Needed() int {
+	return e.Threshold - e.Have
+}
+
+// secretShare is a decoded armored share
+type secretShare struct {
+	id        uint16
+	threshold int
+	digest    []byte
+	shamir.Share
+}
+
+// ArmorShares splits the secret into count armored shares, any threshold of them restore it with
+// CombineArmoredShares. Every share carries a checksum to detect its corruption.
+func ArmorShares(secret []byte, threshold, count int) ([]string, error) {
+	shares, err := shamir.Split(secret, threshold, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(secret)
+
+	armors := make([]string, len(shares))
+	for i, share := range shares {
+		var buf bytes.Buffer
+		buf.WriteByte(shareVersion)
+		buf.Write(id[:])
+		buf.WriteByte(byte(threshold))
+		buf.WriteByte(share.X)
+		buf.Write(digest[:4])
+		buf.Write(share.Value)
+		checksum := sha256.Sum256(buf.Bytes())
+		buf.Write(checksum[:shareChecksumLen])
+
+		header := map[string]string{
+			headerShareID:        fmt.Sprintf("%X", id),
+			headerShareIndex:     fmt.Sprintf("%d/%d", share.X, count),
+			headerShareThreshold: strconv.Itoa(threshold),
+		}
+		armors[i] = armor.EncodeArmor(blockTypeShare, header, buf.Bytes())
+	}
+	return armors, nil
+}
+
+// CombineArmoredShares restores the secret of the shares of ArmorShares. An InsufficientSharesError
+// reports how many more shares are needed.
+func CombineArmoredShares(armors []string) ([]byte, error) {
+	if len(armors) == 0 {
+		return nil, fmt.Errorf("no share")
+	}
+
+	var (
+		first  secretShare
+		shares []shamir.Share
+		seen   = make(map[byte]bool)
+	)
+	for i, a := range armors {
+		share, err := decodeShare(a)
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %s", i+1, err.Error())
+		}
+		if i == 0 {
+			first = share
+		} else if share.id != first.id || share.threshold != first.threshold || !bytes.Equal(share.digest, first.digest) {
+			return nil, fmt.Errorf("share %d belongs to another secret", i+1)
+		}
+		// the same share given twice only counts once
+		if !seen[share.X] {
+			seen[share.X] = true
+			shares = append(shares, share.Share)
+		}
+	}
+
+	if len(shares) < first.threshold {
+		return nil, InsufficientSharesError{Threshold: first.threshold, Have: len(shares)}
+	}
+
+	secret, err := shamir.Combine(shares[:first.threshold])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(secret)
+	if !bytes.Equal(digest[:4], first.digest) {
+		return nil, fmt.Errorf("the shares don't restore their secret, one of them is corrupted")
+	}
+	return secret, nil
+}
+
+func decodeShare(a string) (secretShare, error) {
+	blockType, _, bz, err := armor.DecodeArmor(a)
+	if err != nil {
+		return secretShare{}, fmt.Errorf("invalid armor, the share is corrupted: %s", err.Error())
+	}
+	if blockType != blockTypeShare {
+		return secretShare{}, fmt.Errorf("unrecognized armor type: %v", blockType)
+	}
+	if len(bz) <= shareHeaderLen+shareChecksumLen {
+		return secretShare{}, fmt.Errorf("the share is truncated")
+	}
+
+	body, checksum := bz[:len(bz)-shareChecksumLen], bz[len(bz)-shareChecksumLen:]
+	expected := sha256.Sum256(body)
+	if !bytes.Equal(checksum, expected[:shareChecksumLen]) {
+		return secretShare{}, fmt.Errorf("checksum mismatch, the share is corrupted")
+	}
+	if body[0] != shareVersion {
+		return secretShare{}, fmt.Errorf("unsupported share version %d", body[0])
+	}
+
+	share := secretShare{
+		id:        binary.BigEndian.Uint16(body[1:3]),
+		threshold: int(body[3]),
+		digest:    body[5:9],
+		Share:     shamir.Share{X: body[4], Value: body[shareHeaderLen:]},
+	}
+	if share.threshold == 0 || share.X == 0 {
+		return secretShare{}, fmt.Errorf("invalid share")
+	}
+	return share, nil
+}
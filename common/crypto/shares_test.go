@@ -0,0 +1,55 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/common/crypto"
+)
+
+func TestArmorShares(t *testing.T) {
+	secret := []byte("nerve leader thank marriage spice task van start piece crowd run hospital")
+	shares, err := crypto.ArmorShares(secret, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// every combination of 3 shares restores the secret
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				restored, err := crypto.CombineArmoredShares([]string{shares[k], shares[i], shares[j]})
+				require.NoError(t, err)
+				require.Equal(t, secret, restored)
+			}
+		}
+	}
+
+	_, err = crypto.CombineArmoredShares([]string{shares[0], shares[3], shares[3]})
+	require.EqualError(t, err, "2 of the 3 shares needed, 1 more required")
+	require.Equal(t, 1, err.(crypto.InsufficientSharesError).Needed())
+
+	other, err := crypto.ArmorShares(secret, 3, 5)
+	require.NoError(t, err)
+	_, err = crypto.CombineArmoredShares([]string{shares[0], shares[1], other[2]})
+	require.EqualError(t, err, "share 3 belongs to another secret")
+
+	// flip a character of the body of a share
+	lines := strings.Split(shares[1], "\n")
+	body := []byte(lines[5])
+	if body[10] == 'A' {
+		body[10] = 'B'
+	} else {
+		body[10] = 'A'
+	}
+	lines[5] = string(body)
+	_, err = crypto.CombineArmoredShares([]string{shares[0], strings.Join(lines, "\n"), shares[2]})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "share 2:")
+
+	_, err = crypto.ArmorShares(secret, 4, 3)
+	require.Error(t, err)
+	_, err = crypto.ArmorShares(secret, 2, 256)
+	require.Error(t, err)
+}
@@ -33,6 +33,7 @@ require (
 	github.com/tendermint/tm-db v0.6.4
 	github.com/tjfoc/gmsm v1.4.0
 	golang.org/x/crypto v0.0.0-20211115234514-b4de73f9ece8
+	golang.org/x/text v0.3.6
 	google.golang.org/genproto v0.0.0-20211116182654-e63d96a377c4
 	google.golang.org/grpc v1.41.0
 	google.golang.org/protobuf v1.27.1
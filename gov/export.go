@@ -4,6 +4,7 @@ import (
 	"time"
 
 	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
 )
 
 // expose Gov module api for user
@@ -21,6 +22,14 @@ type Client interface {
 	QueryDeposit(proposalId uint64, depositor string) (QueryDepositResp, sdk.Error)
 	QueryDeposits(proposalId uint64) ([]QueryDepositResp, sdk.Error)
 	QueryTallyResult(proposalId uint64) (QueryTallyResultResp, sdk.Error)
+
+	// the Iterate variants follow the next keys of the pages, the All variants drain them
+	IterateProposals(proposalStatus string, opts query.IterateOptions) *query.PageIterator
+	AllProposals(proposalStatus string) ([]QueryProposalResp, sdk.Error)
+	IterateVotes(proposalId uint64, opts query.IterateOptions) *query.PageIterator
+	AllVotes(proposalId uint64) ([]QueryVoteResp, sdk.Error)
+	IterateDeposits(proposalId uint64, opts query.IterateOptions) *query.PageIterator
+	AllDeposits(proposalId uint64) ([]QueryDepositResp, sdk.Error)
 }
 
 type SubmitProposalRequest struct {
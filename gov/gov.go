@@ -118,7 +118,7 @@ func (gc govClient) QueryProposal(proposalId uint64) (QueryProposalResp, sdk.Err
 }
 
 // if proposalStatus is nil will return all status's proposals
-// about proposalStatus see VoteOption_value
+// about proposalStatus see ProposalStatus_value
 func (gc govClient) QueryProposals(proposalStatus string) ([]QueryProposalResp, sdk.Error) {
 	conn, err := gc.GenConn()
 
@@ -129,7 +129,7 @@ func (gc govClient) QueryProposals(proposalStatus string) ([]QueryProposalResp,
 	res, err := NewQueryClient(conn).Proposals(
 		context.Background(),
 		&QueryProposalsRequest{
-			ProposalStatus: ProposalStatus(VoteOption_value[proposalStatus]),
+			ProposalStatus: ProposalStatus(ProposalStatus_value[proposalStatus]),
 			Pagination: &query.PageRequest{
 				Offset:     0,
 				Limit:      100,
@@ -0,0 +1,123 @@
+package gov
+
+import (
+	"context"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
+)
+
+// IterateProposals iterates the proposals of the status, QueryProposalResp items
+// about proposalStatus see QueryProposals
+func (gc govClient) IterateProposals(proposalStatus string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := gc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).Proposals(
+			context.Background(),
+			&QueryProposalsRequest{
+				ProposalStatus: ProposalStatus(ProposalStatus_value[proposalStatus]),
+				Pagination:     pageReq,
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Proposals))
+		for i, p := range res.Proposals {
+			items[i] = p.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllProposals returns every proposal of the status
+func (gc govClient) AllProposals(proposalStatus string) ([]QueryProposalResp, sdk.Error) {
+	items, err := gc.IterateProposals(proposalStatus, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	proposals := make([]QueryProposalResp, len(items))
+	for i, item := range items {
+		proposals[i] = item.(QueryProposalResp)
+	}
+	return proposals, nil
+}
+
+// IterateVotes iterates the votes of the proposal, QueryVoteResp items
+func (gc govClient) IterateVotes(proposalId uint64, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := gc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).Votes(
+			context.Background(),
+			&QueryVotesRequest{
+				ProposalId: proposalId,
+				Pagination: pageReq,
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Votes))
+		for i, v := range res.Votes {
+			items[i] = v.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllVotes returns every vote of the proposal
+func (gc govClient) AllVotes(proposalId uint64) ([]QueryVoteResp, sdk.Error) {
+	items, err := gc.IterateVotes(proposalId, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	votes := make([]QueryVoteResp, len(items))
+	for i, item := range items {
+		votes[i] = item.(QueryVoteResp)
+	}
+	return votes, nil
+}
+
+// IterateDeposits iterates the deposits of the proposal, QueryDepositResp items
+func (gc govClient) IterateDeposits(proposalId uint64, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := gc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).Deposits(
+			context.Background(),
+			&QueryDepositsRequest{
+				ProposalId: proposalId,
+				Pagination: pageReq,
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Deposits))
+		for i, d := range res.Deposits {
+			items[i] = d.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllDeposits returns every deposit of the proposal
+func (gc govClient) AllDeposits(proposalId uint64) ([]QueryDepositResp, sdk.Error) {
+	items, err := gc.IterateDeposits(proposalId, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	deposits := make([]QueryDepositResp, len(items))
+	for i, item := range items {
+		deposits[i] = item.(QueryDepositResp)
+	}
+	return deposits, nil
+}
@@ -2,6 +2,7 @@ package transfer
 
 import (
 	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
 )
 
 // expose transfer module api for user
@@ -12,6 +13,10 @@ type Client interface {
 
 	QueryDenomTrace(request QueryDenomTraceRequest) (QueryDenomTraceResponse, sdk.Error)
 	QueryDenomTraces(request QueryDenomTracesRequest) (QueryDenomTracesResponse, sdk.Error)
+
+	// IterateDenomTraces follows the next keys of the pages, AllDenomTraces drains them
+	IterateDenomTraces(opts query.IterateOptions) *query.PageIterator
+	AllDenomTraces() (Traces, sdk.Error)
 }
 
 type TransferRequest struct {
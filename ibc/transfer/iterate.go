@@ -0,0 +1,46 @@
+package transfer
+
+import (
+	"context"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
+)
+
+// IterateDenomTraces iterates the denom traces, DenomTrace items
+func (tc transferClient) IterateDenomTraces(opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := tc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).DenomTraces(
+			context.Background(),
+			&QueryDenomTracesRequest{
+				Pagination: pageReq,
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.DenomTraces))
+		for i, trace := range res.DenomTraces {
+			items[i] = trace
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllDenomTraces returns every denom trace
+func (tc transferClient) AllDenomTraces() (Traces, sdk.Error) {
+	items, err := tc.IterateDenomTraces(query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	traces := make(Traces, len(items))
+	for i, item := range items {
+		traces[i] = item.(DenomTrace)
+	}
+	return traces, nil
+}
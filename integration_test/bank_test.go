@@ -56,9 +56,10 @@ func send(s IntegrationTestSuite) {
 	to := s.GetRandAccount().Address.String()
 
 	ch := make(chan int)
-	s.Bank.SubscribeSendTx(s.Account().Address.String(), to, func(send bank.EventDataMsgSend) {
+	_, err = s.Bank.SubscribeMsgSend(s.Account().Address.String(), to, func(send bank.EventDataMsgSend) {
 		ch <- 1
 	})
+	s.NoError(err)
 
 	baseTx := types.BaseTx{
 		From:               s.Account().Name,
@@ -80,6 +81,19 @@ func send(s IntegrationTestSuite) {
 	s.Equal(resp.Result.Code, uint32(0))
 	s.Equal(resp.Height, res.Height)
 
+	builder := types.NewEventQueryBuilder().AddCondition(
+		types.NewCond(types.EventTypeMessage, types.AttributeKeySender).EQ(types.EventValue(s.Account().Address.String())),
+	)
+	it, err := s.Manager().IterateTxs(builder, types.TxSearchOptions{
+		Order: types.TxSearchOrderDesc,
+		Limit: 1,
+	})
+	s.NoError(err)
+	s.True(it.Next())
+	s.Equal(res.Hash, it.Value().Hash)
+	s.False(it.Next())
+	s.NoError(it.Err())
+
 	<-ch
 }
 
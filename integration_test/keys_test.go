@@ -38,4 +38,23 @@ func (s IntegrationTestSuite) TestKeys() {
 	address4, err := s.Key.RecoverWithHDPath(name, password, mnemonic, "")
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), address, address4)
+
+	// test lifecycle
+	newName, newPassword := s.RandStringOfLength(20), s.RandStringOfLength(8)
+	require.NoError(s.T(), s.Key.Rename(name, newName, password))
+	require.NoError(s.T(), s.Key.ChangePassword(newName, password, newPassword))
+
+	metadata, err := s.Key.ListMetadata()
+	require.NoError(s.T(), err)
+	var found bool
+	for _, m := range metadata {
+		if m.Name == newName {
+			found = true
+			require.Equal(s.T(), address, m.Address)
+		}
+	}
+	require.True(s.T(), found)
+
+	err = s.Key.Delete(newName, newPassword)
+	require.NoError(s.T(), err)
 }
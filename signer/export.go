@@ -0,0 +1,64 @@
+package signer
+
+import (
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+)
+
+// http routes served by the signer
+const (
+	routeSign = "/v1/sign"
+	routeFind = "/v1/find"
+	routeKeys = "/v1/keys"
+)
+
+// SignRequest is a signature request checked by the policies of the signer
+type SignRequest struct {
+	// Name of the key
+	Name string
+	// Address of the key
+	Address string
+	// Client is the common name of the client certificate
+	Client    string
+	SignBytes []byte
+
+	// The fields below are decoded from SIGN_MODE_DIRECT sign bytes, other sign modes are
+	// rejected as soon as a policy is configured
+	ChainID string
+	Msgs    []sdk.Msg
+	Fee     sdk.Coins
+}
+
+// Policy approves a signature request by returning nil
+type Policy func(req SignRequest) error
+
+type signRequest struct {
+	Name      string `json:"name"`
+	Password  string `json:"password"`
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+type signResponse struct {
+	Signature []byte `json:"signature"`
+	// PubKey is amino encoded
+	PubKey []byte `json:"pubkey"`
+}
+
+type findRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type findResponse struct {
+	// PubKey is amino encoded
+	PubKey  []byte `json:"pubkey"`
+	Address string `json:"address"`
+}
+
+type keysResponse struct {
+	Keys []store.KeyMetadata `json:"keys"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+// maxMsgDepth bounds the nesting of the msgs executed by other msgs
+const maxMsgDepth = 8
+
+// SpendFunc returns the coins the msg spends from the address
+type SpendFunc func(address string, msg sdk.Msg) sdk.Coins
+
+// nestedMsgs is implemented by the msgs executing other msgs, e.g. authz MsgExec
+type nestedMsgs interface {
+	GetMessages() ([]sdk.Msg, error)
+}
+
+// signedMsg is a msg of the tx or executed by one, with the address it spends from: the key for
+// the msgs of the tx, the first signer, i.e. the granter, for the msgs executed on its behalf
+type signedMsg struct {
+	address string
+	msg     sdk.Msg
+}
+
+// flattenMsgs returns the msgs of the request followed by the msgs they execute, recursively
+func flattenMsgs(req SignRequest) ([]signedMsg, error) {
+	msgs := make([]signedMsg, 0, len(req.Msgs))
+	for _, msg := range req.Msgs {
+		msgs = append(msgs, signedMsg{address: req.Address, msg: msg})
+	}
+
+	for i, depth := 0, 0; i < len(msgs); depth++ {
+		if depth > maxMsgDepth {
+			return nil, fmt.Errorf("msgs nested deeper than %d", maxMsgDepth)
+		}
+		end := len(msgs)
+		for ; i < end; i++ {
+			nested, ok := msgs[i].msg.(nestedMsgs)
+			if !ok {
+				continue
+			}
+			inner, err := nested.GetMessages()
+			if err != nil {
+				return nil, fmt.Errorf("msgs executed by %s can not be checked: %s", sdk.MsgTypeURL(msgs[i].msg), err.Error())
+			}
+			for _, msg := range inner {
+				address := msgs[i].address
+				if signers := msg.GetSigners(); len(signers) > 0 {
+					address = signers[0].String()
+				}
+				msgs = append(msgs, signedMsg{address: address, msg: msg})
+			}
+		}
+	}
+	return msgs, nil
+}
+
+// AllowMsgTypes rejects every tx containing a msg whose type url is not listed, e.g. "/cosmos.bank.v1beta1.MsgSend".
+// The msgs executed by a msg, e.g. by authz MsgExec, must be listed too.
+func AllowMsgTypes(typeURLs ...string) Policy {
+	allowed := make(map[string]bool, len(typeURLs))
+	for _, typeURL := range typeURLs {
+		allowed[typeURL] = true
+	}
+
+	return func(req SignRequest) error {
+		msgs, err := flattenMsgs(req)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			typeURL := sdk.MsgTypeURL(m.msg)
+			if !allowed[typeURL] {
+				return fmt.Errorf("msg type %s is not allowed", typeURL)
+			}
+		}
+		return nil
+	}
+}
+
+// TxSpendLimit caps the coins each tx can spend with a key, fees included. It is a cap per tx,
+// not a budget: the txs signed one after another are not added up.
+// Keys without limit are not restricted. The coins a key spends on behalf of a granter through
+// authz MsgExec count as spent by the key. Bank sends are always accounted for, other msgs only
+// through the given spend funcs, so combine it with AllowMsgTypes.
+func TxSpendLimit(limits map[string]sdk.Coins, spends ...SpendFunc) Policy {
+	spends = append([]SpendFunc{BankSpend}, spends...)
+
+	return func(req SignRequest) error {
+		limit, ok := limits[req.Name]
+		if !ok {
+			return nil
+		}
+
+		msgs, err := flattenMsgs(req)
+		if err != nil {
+			return err
+		}
+
+		spent := req.Fee
+		for _, m := range msgs {
+			for _, spend := range spends {
+				if coins := spend(m.address, m.msg); !coins.Empty() {
+					spent = spent.Add(coins...)
+				}
+			}
+		}
+
+		if !spent.IsAllLTE(limit) {
+			return fmt.Errorf("spending %s exceeds the limit %s of %s", spent, limit, req.Name)
+		}
+		return nil
+	}
+}
+
+// BankSpend returns the coins sent from the address by MsgSend and MsgMultiSend
+func BankSpend(address string, msg sdk.Msg) sdk.Coins {
+	switch msg := msg.(type) {
+	case *bank.MsgSend:
+		if msg.FromAddress == address {
+			return msg.Amount
+		}
+	case *bank.MsgMultiSend:
+		var coins sdk.Coins
+		for _, input := range msg.Inputs {
+			if input.Address == address {
+				coins = coins.Add(input.Coins...)
+			}
+		}
+		return coins
+	}
+	return nil
+}
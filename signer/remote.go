@@ -0,0 +1,185 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/irisnet/core-sdk-go/client"
+	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+const defaultRemoteTimeout = 10 * time.Second
+
+var (
+	_ sdk.Signer    = &RemoteKeyManager{}
+	_ sdk.KeyLister = &RemoteKeyManager{}
+)
+
+// RemoteKeyManagerConfig configures a RemoteKeyManager
+type RemoteKeyManagerConfig struct {
+	// Endpoint of the signer, e.g. https://signer:8443
+	Endpoint string
+	// TLSConfig holds the client certificate and the CA of the signer, see NewTLSConfig
+	TLSConfig *tls.Config
+	// Timeout of a request, defaults to 10s
+	Timeout time.Duration
+}
+
+// RemoteKeyManager signs with keys held by a separate signer, pass it to the client with types.SignerOption.
+// It is a Signer and a KeyLister, the keys are administrated on the signer.
+type RemoteKeyManager struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewRemoteKeyManager creates a key manager backed by a remote signer
+func NewRemoteKeyManager(cfg RemoteKeyManagerConfig) (*RemoteKeyManager, error) {
+	if !strings.HasPrefix(cfg.Endpoint, "https://") {
+		return nil, fmt.Errorf("the signer endpoint must use https")
+	}
+	if cfg.TLSConfig == nil || len(cfg.TLSConfig.Certificates) == 0 {
+		return nil, fmt.Errorf("a client certificate is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultRemoteTimeout
+	}
+
+	return &RemoteKeyManager{
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{TLSClientConfig: cfg.TLSConfig},
+		},
+	}, nil
+}
+
+func (r *RemoteKeyManager) Sign(name, password string, data []byte) ([]byte, tmcrypto.PubKey, error) {
+	var res signResponse
+	if err := r.post(routeSign, signRequest{Name: name, Password: password, SignBytes: data}, &res); err != nil {
+		return nil, nil, err
+	}
+
+	pubKey, err := cryptoamino.PubKeyFromBytes(res.PubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Signature, pubKey, nil
+}
+
+func (r *RemoteKeyManager) Find(name, password string) (tmcrypto.PubKey, sdk.AccAddress, error) {
+	var res findResponse
+	if err := r.post(routeFind, findRequest{Name: name, Password: password}, &res); err != nil {
+		return nil, nil, sdk.WrapWithMessage(err, "name %s not exist", name)
+	}
+
+	pubKey, err := cryptoamino.PubKeyFromBytes(res.PubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	address, err := sdk.AccAddressFromBech32(res.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pubKey, address, nil
+}
+
+func (r *RemoteKeyManager) List() ([]string, error) {
+	metadata, err := r.ListMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(metadata))
+	for i, m := range metadata {
+		names[i] = m.Name
+	}
+	return names, nil
+}
+
+func (r *RemoteKeyManager) ListMetadata() ([]sdk.KeyMetadata, error) {
+	var res keysResponse
+	if err := r.do(http.MethodGet, routeKeys, nil, &res); err != nil {
+		return nil, err
+	}
+
+	metadata := make([]sdk.KeyMetadata, len(res.Keys))
+	for i, m := range res.Keys {
+		var err error
+		if metadata[i], err = client.ParseKeyMetadata(m); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
+
+func (r *RemoteKeyManager) post(route string, req, res interface{}) error {
+	bz, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return r.do(http.MethodPost, route, bz, res)
+}
+
+func (r *RemoteKeyManager) do(method, route string, body []byte, res interface{}) error {
+	req, err := http.NewRequest(method, r.endpoint+route, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errRes errorResponse
+		if err := json.Unmarshal(bz, &errRes); err != nil || len(errRes.Error) == 0 {
+			return fmt.Errorf("signer returned %s", resp.Status)
+		}
+		return fmt.Errorf("signer: %s", errRes.Error)
+	}
+	return json.Unmarshal(bz, res)
+}
+
+// NewTLSConfig loads a certificate and the CA trusted to authenticate the other side. The same
+// config serves both the client and the server: the server requires and verifies client certificates.
+func NewTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	caBz, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBz) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
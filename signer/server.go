@@ -0,0 +1,258 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/irisnet/core-sdk-go/client"
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	cryptoamino "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	sdklog "github.com/irisnet/core-sdk-go/common/log"
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+	typetx "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+const maxRequestBytes = 1 << 20
+
+// ServerConfig configures a signer Server
+type ServerConfig struct {
+	// KeyDAO holds the keys of the signer
+	KeyDAO store.KeyDAO
+	// InterfaceRegistry resolves the msgs of the sign bytes for the policies, it must know every
+	// msg type the clients sign
+	InterfaceRegistry codectypes.InterfaceRegistry
+	// Policies must all approve a request before it is signed
+	Policies []Policy
+	// Password returns the password of a key held by the signer. When it is nil or returns false,
+	// the password sent by the client is used.
+	Password func(name string) (string, bool)
+	Logger   log.Logger
+}
+
+// Server is a reference signer exposing a KeyDAO over http, it must be served with mutual TLS
+type Server struct {
+	cfg ServerConfig
+	km  client.KeyManager
+	mux *http.ServeMux
+}
+
+// NewServer creates a signer server
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if cfg.KeyDAO == nil {
+		return nil, fmt.Errorf("KeyDAO is required")
+	}
+	if len(cfg.Policies) > 0 && cfg.InterfaceRegistry == nil {
+		return nil, fmt.Errorf("InterfaceRegistry is required by the policies")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = sdklog.NewDefaultLogger()
+	}
+
+	s := &Server{
+		cfg: cfg,
+		km:  client.NewKeyManager(cfg.KeyDAO, ""),
+		mux: http.NewServeMux(),
+	}
+	s.mux.HandleFunc(routeSign, s.handleSign)
+	s.mux.HandleFunc(routeFind, s.handleFind)
+	s.mux.HandleFunc(routeKeys, s.handleKeys)
+	return s, nil
+}
+
+// ListenAndServeTLS serves the signer on the address, the TLS config must verify client certificates
+func (s *Server) ListenAndServeTLS(addr string, tlsConfig *tls.Config) error {
+	if tlsConfig == nil || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		return fmt.Errorf("the signer requires mutual TLS")
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.cfg.Logger.Info("start signer", "addr", addr)
+	return http.Serve(tls.NewListener(listener, tlsConfig), s)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	password := s.password(req.Name, req.Password)
+
+	_, address, err := s.km.Find(req.Name, password)
+	if err != nil {
+		s.error(w, http.StatusNotFound, err)
+		return
+	}
+
+	signReq := SignRequest{
+		Name:      req.Name,
+		Address:   address.String(),
+		Client:    clientName(r),
+		SignBytes: req.SignBytes,
+	}
+	if err := s.authorize(signReq); err != nil {
+		s.cfg.Logger.Info("signature rejected", "name", req.Name, "client", signReq.Client, "reason", err.Error())
+		s.error(w, http.StatusForbidden, err)
+		return
+	}
+
+	signature, pubKey, err := s.km.Sign(req.Name, password, req.SignBytes)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.cfg.Logger.Info("signed", "name", req.Name, "client", signReq.Client)
+	s.write(w, signResponse{
+		Signature: signature,
+		PubKey:    cryptoamino.MarshalPubkey(pubKey),
+	})
+}
+
+func (s *Server) handleFind(w http.ResponseWriter, r *http.Request) {
+	var req findRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+
+	pubKey, address, err := s.km.Find(req.Name, s.password(req.Name, req.Password))
+	if err != nil {
+		s.error(w, http.StatusNotFound, err)
+		return
+	}
+	s.write(w, findResponse{
+		PubKey:  cryptoamino.MarshalPubkey(pubKey),
+		Address: address.String(),
+	})
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.error(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	keys, err := s.cfg.KeyDAO.ListMetadata()
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.write(w, keysResponse{Keys: keys})
+}
+
+// authorize runs the policies, the sign bytes are only decoded when there is a policy
+func (s *Server) authorize(req SignRequest) error {
+	if len(s.cfg.Policies) == 0 {
+		return nil
+	}
+
+	if err := decodeSignDoc(s.cfg.InterfaceRegistry, &req); err != nil {
+		return fmt.Errorf("sign bytes can not be checked: %s", err.Error())
+	}
+	for _, policy := range s.cfg.Policies {
+		if err := policy(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) password(name, password string) string {
+	if s.cfg.Password == nil {
+		return password
+	}
+	if p, ok := s.cfg.Password(name); ok {
+		return p
+	}
+	return password
+}
+
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		s.error(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return false
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(req); err != nil {
+		s.error(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func (s *Server) write(w http.ResponseWriter, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.cfg.Logger.Error("write response failed", "errMsg", err.Error())
+	}
+}
+
+func (s *Server) error(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+// decodeSignDoc fills the request with the content of SIGN_MODE_DIRECT sign bytes. Protobuf
+// decodes many arbitrary inputs without error, so the sign bytes must be the canonical encoding
+// of a doc carrying msgs, otherwise the policies would approve an empty tx.
+func decodeSignDoc(unpacker codectypes.AnyUnpacker, req *SignRequest) error {
+	var doc typetx.SignDoc
+	if err := doc.Unmarshal(req.SignBytes); err != nil {
+		return err
+	}
+	if bz, err := doc.Marshal(); err != nil || !bytes.Equal(bz, req.SignBytes) {
+		return fmt.Errorf("not a SIGN_MODE_DIRECT sign doc")
+	}
+
+	var body typetx.TxBody
+	if err := body.Unmarshal(doc.BodyBytes); err != nil {
+		return err
+	}
+	if err := body.UnpackInterfaces(unpacker); err != nil {
+		return err
+	}
+
+	var authInfo typetx.AuthInfo
+	if err := authInfo.Unmarshal(doc.AuthInfoBytes); err != nil {
+		return err
+	}
+
+	if len(body.Messages) == 0 {
+		return fmt.Errorf("no msg")
+	}
+
+	req.ChainID = doc.ChainId
+	req.Msgs = make([]sdk.Msg, len(body.Messages))
+	for i, any := range body.Messages {
+		msg, ok := any.GetCachedValue().(sdk.Msg)
+		if !ok {
+			return fmt.Errorf("unknown msg type %s", any.TypeUrl)
+		}
+		req.Msgs[i] = msg
+	}
+	if authInfo.Fee != nil {
+		req.Fee = authInfo.Fee.Amount
+	}
+	return nil
+}
+
+func clientName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return r.TLS.PeerCertificates[0].Subject.CommonName
+}
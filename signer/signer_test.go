@@ -0,0 +1,181 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	"github.com/irisnet/core-sdk-go/client"
+	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/store"
+	typetx "github.com/irisnet/core-sdk-go/types/tx"
+)
+
+func TestRemoteKeyManager(t *testing.T) {
+	dir := t.TempDir()
+	writeCerts(t, dir)
+
+	serverTLS, err := NewTLSConfig(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem"))
+	require.NoError(t, err)
+	clientTLS, err := NewTLSConfig(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.pem"))
+	require.NoError(t, err)
+
+	dao := store.NewMemory(nil)
+	local := client.KeyManager{KeyDAO: dao, Algo: "secp256k1"}
+	address, _, err := local.Insert("hot", "12345678")
+	require.NoError(t, err)
+
+	registry := codectypes.NewInterfaceRegistry()
+	bank.RegisterInterfaces(registry)
+	server, err := NewServer(ServerConfig{
+		KeyDAO:            dao,
+		InterfaceRegistry: registry,
+		Policies: []Policy{
+			AllowMsgTypes("/cosmos.bank.v1beta1.MsgSend"),
+			TxSpendLimit(map[string]sdk.Coins{"hot": sdk.NewCoins(sdk.NewInt64Coin("uiris", 100))}),
+		},
+		Password: func(name string) (string, bool) { return "12345678", name == "hot" },
+	})
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(server)
+	ts.TLS = serverTLS
+	ts.StartTLS()
+	defer ts.Close()
+
+	// a client without certificate is refused
+	_, err = NewRemoteKeyManager(RemoteKeyManagerConfig{Endpoint: ts.URL, TLSConfig: &tls.Config{}})
+	require.Error(t, err)
+
+	remote, err := NewRemoteKeyManager(RemoteKeyManagerConfig{Endpoint: ts.URL, TLSConfig: clientTLS})
+	require.NoError(t, err)
+
+	pubKey, addr, err := remote.Find("hot", "")
+	require.NoError(t, err)
+	require.Equal(t, address, addr.String())
+
+	names, err := remote.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"hot"}, names)
+
+	signBytes := signDoc(t, 90, &bank.MsgSend{FromAddress: address, ToAddress: address, Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 10))})
+	signature, signPubKey, err := remote.Sign("hot", "", signBytes)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(signBytes, signature))
+	require.Equal(t, pubKey.Bytes(), signPubKey.Bytes())
+
+	// the fee exceeds the spend limit
+	_, _, err = remote.Sign("hot", "", signDoc(t, 91, &bank.MsgSend{FromAddress: address, ToAddress: address, Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 10))}))
+	require.Error(t, err)
+
+	// the msg type is not allowed
+	_, _, err = remote.Sign("hot", "", signDoc(t, 1, &bank.MsgMultiSend{}))
+	require.Error(t, err)
+
+	// arbitrary bytes can not be checked by the policies
+	_, _, err = remote.Sign("hot", "", []byte("arbitrary"))
+	require.Error(t, err)
+}
+
+// execMsg executes msgs on behalf of their signers like authz MsgExec
+type execMsg struct {
+	*bank.MsgSend
+	msgs []sdk.Msg
+}
+
+func (m execMsg) GetMessages() ([]sdk.Msg, error) {
+	return m.msgs, nil
+}
+
+func TestPoliciesUnwrapNestedMsgs(t *testing.T) {
+	grantee, granter := sdk.AccAddress("grantee_____________").String(), sdk.AccAddress("granter_____________").String()
+	send := &bank.MsgSend{FromAddress: granter, ToAddress: grantee, Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 60))}
+	exec := execMsg{MsgSend: &bank.MsgSend{FromAddress: grantee}, msgs: []sdk.Msg{execMsg{MsgSend: &bank.MsgSend{FromAddress: grantee}, msgs: []sdk.Msg{send}}}}
+	req := SignRequest{Name: "hot", Address: grantee, Msgs: []sdk.Msg{exec}}
+
+	// the executed msg type must be allowed
+	require.Error(t, AllowMsgTypes(sdk.MsgTypeURL(exec))(req))
+	require.NoError(t, AllowMsgTypes(sdk.MsgTypeURL(exec), "/cosmos.bank.v1beta1.MsgSend")(req))
+
+	// the coins of the granter spent with the key count
+	require.Error(t, TxSpendLimit(map[string]sdk.Coins{"hot": sdk.NewCoins(sdk.NewInt64Coin("uiris", 50))})(req))
+	require.NoError(t, TxSpendLimit(map[string]sdk.Coins{"hot": sdk.NewCoins(sdk.NewInt64Coin("uiris", 60))})(req))
+}
+
+func signDoc(t *testing.T, fee int64, msg sdk.Msg) []byte {
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	body := typetx.TxBody{Messages: []*codectypes.Any{any}}
+	bodyBz, err := body.Marshal()
+	require.NoError(t, err)
+
+	authInfo := typetx.AuthInfo{Fee: &typetx.Fee{Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", fee)), GasLimit: 200000}}
+	authInfoBz, err := authInfo.Marshal()
+	require.NoError(t, err)
+
+	doc := typetx.SignDoc{BodyBytes: bodyBz, AuthInfoBytes: authInfoBz, ChainId: "test"}
+	bz, err := doc.Marshal()
+	require.NoError(t, err)
+	return bz
+}
+
+// writeCerts writes a CA, a server certificate for 127.0.0.1 and a client certificate
+func writeCerts(t *testing.T, dir string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", caDER)
+
+	for i, name := range []string{"server", "client"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+	}
+}
+
+func writePEM(t *testing.T, filename, typ string, der []byte) {
+	require.NoError(t, ioutil.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+}
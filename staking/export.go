@@ -4,6 +4,7 @@ import (
 	"time"
 
 	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
 )
 
 // expose Staking module api for user
@@ -30,6 +31,22 @@ type Client interface {
 	QueryHistoricalInfo(height int64) (QueryHistoricalInfoResp, sdk.Error)
 	QueryPool() (QueryPoolResp, sdk.Error)
 	QueryParams() (QueryParamsResp, sdk.Error)
+
+	// the Iterate variants follow the next keys of the pages, the All variants drain them
+	IterateValidators(status string, opts query.IterateOptions) *query.PageIterator
+	AllValidators(status string) ([]QueryValidatorResp, sdk.Error)
+	IterateValidatorDelegations(validatorAddr string, opts query.IterateOptions) *query.PageIterator
+	AllValidatorDelegations(validatorAddr string) ([]QueryDelegationResp, sdk.Error)
+	IterateValidatorUnbondingDelegations(validatorAddr string, opts query.IterateOptions) *query.PageIterator
+	AllValidatorUnbondingDelegations(validatorAddr string) ([]QueryUnbondingDelegationResp, sdk.Error)
+	IterateDelegatorDelegations(delegatorAddr string, opts query.IterateOptions) *query.PageIterator
+	AllDelegatorDelegations(delegatorAddr string) ([]QueryDelegationResp, sdk.Error)
+	IterateDelegatorUnbondingDelegations(delegatorAddr string, opts query.IterateOptions) *query.PageIterator
+	AllDelegatorUnbondingDelegations(delegatorAddr string) ([]QueryUnbondingDelegationResp, sdk.Error)
+	IterateRedelegations(request QueryRedelegationsReq, opts query.IterateOptions) *query.PageIterator
+	AllRedelegations(request QueryRedelegationsReq) ([]RedelegationResp, sdk.Error)
+	IterateDelegatorValidators(delegatorAddr string, opts query.IterateOptions) *query.PageIterator
+	AllDelegatorValidators(delegatorAddr string) ([]QueryValidatorResp, sdk.Error)
 }
 
 type CreateValidatorRequest struct {
@@ -0,0 +1,268 @@
+package staking
+
+import (
+	"context"
+
+	sdk "github.com/irisnet/core-sdk-go/types"
+	"github.com/irisnet/core-sdk-go/types/query"
+)
+
+// IterateValidators iterates the validators of the status, QueryValidatorResp items
+func (sc stakingClient) IterateValidators(status string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).Validators(
+			context.Background(),
+			&QueryValidatorsRequest{Status: status, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Validators))
+		for i, v := range res.Validators {
+			items[i] = v.Convert(sc.Marshaler)
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllValidators returns every validator of the status
+func (sc stakingClient) AllValidators(status string) ([]QueryValidatorResp, sdk.Error) {
+	items, err := sc.IterateValidators(status, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toValidators(items), nil
+}
+
+// IterateValidatorDelegations iterates the delegations to the validator, QueryDelegationResp items
+func (sc stakingClient) IterateValidatorDelegations(validatorAddr string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).ValidatorDelegations(
+			context.Background(),
+			&QueryValidatorDelegationsRequest{ValidatorAddr: validatorAddr, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.DelegationResponses))
+		for i, d := range res.DelegationResponses {
+			items[i] = d.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllValidatorDelegations returns every delegation to the validator
+func (sc stakingClient) AllValidatorDelegations(validatorAddr string) ([]QueryDelegationResp, sdk.Error) {
+	items, err := sc.IterateValidatorDelegations(validatorAddr, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toDelegations(items), nil
+}
+
+// IterateValidatorUnbondingDelegations iterates the unbonding delegations from the validator,
+// QueryUnbondingDelegationResp items
+func (sc stakingClient) IterateValidatorUnbondingDelegations(validatorAddr string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).ValidatorUnbondingDelegations(
+			context.Background(),
+			&QueryValidatorUnbondingDelegationsRequest{ValidatorAddr: validatorAddr, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.UnbondingResponses))
+		for i, u := range res.UnbondingResponses {
+			items[i] = u.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllValidatorUnbondingDelegations returns every unbonding delegation from the validator
+func (sc stakingClient) AllValidatorUnbondingDelegations(validatorAddr string) ([]QueryUnbondingDelegationResp, sdk.Error) {
+	items, err := sc.IterateValidatorUnbondingDelegations(validatorAddr, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toUnbondingDelegations(items), nil
+}
+
+// IterateDelegatorDelegations iterates the delegations of the delegator, QueryDelegationResp items
+func (sc stakingClient) IterateDelegatorDelegations(delegatorAddr string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).DelegatorDelegations(
+			context.Background(),
+			&QueryDelegatorDelegationsRequest{DelegatorAddr: delegatorAddr, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.DelegationResponses))
+		for i, d := range res.DelegationResponses {
+			items[i] = d.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllDelegatorDelegations returns every delegation of the delegator
+func (sc stakingClient) AllDelegatorDelegations(delegatorAddr string) ([]QueryDelegationResp, sdk.Error) {
+	items, err := sc.IterateDelegatorDelegations(delegatorAddr, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toDelegations(items), nil
+}
+
+// IterateDelegatorUnbondingDelegations iterates the unbonding delegations of the delegator,
+// QueryUnbondingDelegationResp items
+func (sc stakingClient) IterateDelegatorUnbondingDelegations(delegatorAddr string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).DelegatorUnbondingDelegations(
+			context.Background(),
+			&QueryDelegatorUnbondingDelegationsRequest{DelegatorAddr: delegatorAddr, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.UnbondingResponses))
+		for i, u := range res.UnbondingResponses {
+			items[i] = u.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllDelegatorUnbondingDelegations returns every unbonding delegation of the delegator
+func (sc stakingClient) AllDelegatorUnbondingDelegations(delegatorAddr string) ([]QueryUnbondingDelegationResp, sdk.Error) {
+	items, err := sc.IterateDelegatorUnbondingDelegations(delegatorAddr, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toUnbondingDelegations(items), nil
+}
+
+// IterateRedelegations iterates the redelegations of the request, its Page and Size are ignored,
+// RedelegationResp items
+func (sc stakingClient) IterateRedelegations(request QueryRedelegationsReq, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).Redelegations(
+			context.Background(),
+			&QueryRedelegationsRequest{
+				DelegatorAddr:    request.DelegatorAddr,
+				SrcValidatorAddr: request.SrcValidatorAddr,
+				DstValidatorAddr: request.DstValidatorAddr,
+				Pagination:       pageReq,
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.RedelegationResponses))
+		for i, r := range res.RedelegationResponses {
+			items[i] = r.Convert()
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllRedelegations returns every redelegation of the request, its Page and Size are ignored
+func (sc stakingClient) AllRedelegations(request QueryRedelegationsReq) ([]RedelegationResp, sdk.Error) {
+	items, err := sc.IterateRedelegations(request, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	redelegations := make([]RedelegationResp, len(items))
+	for i, item := range items {
+		redelegations[i] = item.(RedelegationResp)
+	}
+	return redelegations, nil
+}
+
+// IterateDelegatorValidators iterates the validators the delegator delegated to, QueryValidatorResp items
+func (sc stakingClient) IterateDelegatorValidators(delegatorAddr string, opts query.IterateOptions) *query.PageIterator {
+	return query.NewPageIterator(func(pageReq *query.PageRequest) ([]interface{}, *query.PageResponse, error) {
+		conn, err := sc.GenConn()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, err := NewQueryClient(conn).DelegatorValidators(
+			context.Background(),
+			&QueryDelegatorValidatorsRequest{DelegatorAddr: delegatorAddr, Pagination: pageReq},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(res.Validators))
+		for i, v := range res.Validators {
+			items[i] = v.Convert(sc.Marshaler)
+		}
+		return items, res.Pagination, nil
+	}, opts)
+}
+
+// AllDelegatorValidators returns every validator the delegator delegated to
+func (sc stakingClient) AllDelegatorValidators(delegatorAddr string) ([]QueryValidatorResp, sdk.Error) {
+	items, err := sc.IterateDelegatorValidators(delegatorAddr, query.IterateOptions{Prefetch: true}).All()
+	if err != nil {
+		return nil, sdk.Wrap(err)
+	}
+	return toValidators(items), nil
+}
+
+func toValidators(items []interface{}) []QueryValidatorResp {
+	validators := make([]QueryValidatorResp, len(items))
+	for i, item := range items {
+		validators[i] = item.(QueryValidatorResp)
+	}
+	return validators
+}
+
+func toDelegations(items []interface{}) []QueryDelegationResp {
+	delegations := make([]QueryDelegationResp, len(items))
+	for i, item := range items {
+		delegations[i] = item.(QueryDelegationResp)
+	}
+	return delegations
+}
+
+func toUnbondingDelegations(items []interface{}) []QueryUnbondingDelegationResp {
+	unbondingDelegations := make([]QueryUnbondingDelegationResp, len(items))
+	for i, item := range items {
+		unbondingDelegations[i] = item.(QueryUnbondingDelegationResp)
+	}
+	return unbondingDelegations
+}
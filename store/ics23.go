@@ -0,0 +1,468 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/ripemd160" // nolint: staticcheck
+	"golang.org/x/crypto/sha3"
+
+	ics23 "github.com/irisnet/core-sdk-go/third_party/github.com/confio/ics23/go"
+)
+
+// The verification of the ICS23 commitment proofs, as done by github.com/confio/ics23/go on the
+// vendored proto types. The upstream package can't be linked next to them, both register the ics23
+// proto types. testdata/multistore_proofs.json holds proofs of the cosmos-sdk multistore checking it.
+
+// IavlSpec constrains the proofs of the IAVL substores
+var IavlSpec = &ics23.ProofSpec{
+	LeafSpec: &ics23.LeafOp{
+		Prefix:       []byte{0},
+		Hash:         ics23.HashOp_SHA256,
+		PrehashValue: ics23.HashOp_SHA256,
+		Length:       ics23.LengthOp_VAR_PROTO,
+	},
+	InnerSpec: &ics23.InnerSpec{
+		ChildOrder:      []int32{0, 1},
+		MinPrefixLength: 4,
+		MaxPrefixLength: 12,
+		ChildSize:       33, // the length byte and the hash
+		Hash:            ics23.HashOp_SHA256,
+	},
+}
+
+// TendermintSpec constrains the simple merkle proofs of the multistore
+var TendermintSpec = &ics23.ProofSpec{
+	LeafSpec: &ics23.LeafOp{
+		Prefix:       []byte{0},
+		Hash:         ics23.HashOp_SHA256,
+		PrehashValue: ics23.HashOp_SHA256,
+		Length:       ics23.LengthOp_VAR_PROTO,
+	},
+	InnerSpec: &ics23.InnerSpec{
+		ChildOrder:      []int32{0, 1},
+		MinPrefixLength: 1,
+		MaxPrefixLength: 1,
+		ChildSize:       32,
+		Hash:            ics23.HashOp_SHA256,
+	},
+}
+
+// VerifyMembership returns nil when the proof proves the key holds the value under the root
+func VerifyMembership(spec *ics23.ProofSpec, root []byte, proof *ics23.CommitmentProof, key, value []byte) error {
+	exist := existenceProofForKey(proof, key)
+	if exist == nil {
+		return fmt.Errorf("the proof has no existence proof of the key %X", key)
+	}
+	return verifyExistence(exist, spec, root, key, value)
+}
+
+// VerifyNonMembership returns nil when the proof proves the key is absent under the root
+func VerifyNonMembership(spec *ics23.ProofSpec, root []byte, proof *ics23.CommitmentProof, key []byte) error {
+	nonExist := nonExistenceProofForKey(proof, key)
+	if nonExist == nil {
+		return fmt.Errorf("the proof has no non-existence proof of the key %X", key)
+	}
+	return verifyNonExistence(nonExist, spec, root, key)
+}
+
+// CalculateRoot returns the root the proof commits to
+func CalculateRoot(proof *ics23.CommitmentProof) ([]byte, error) {
+	switch {
+	case proof.GetExist() != nil:
+		return calculateExistence(proof.GetExist())
+	case proof.GetNonexist() != nil:
+		nonExist := proof.GetNonexist()
+		if nonExist.Left != nil {
+			return calculateExistence(nonExist.Left)
+		}
+		if nonExist.Right != nil {
+			return calculateExistence(nonExist.Right)
+		}
+		return nil, fmt.Errorf("the non-existence proof has no neighbour")
+	case proof.GetBatch() != nil:
+		entries := proof.GetBatch().Entries
+		if len(entries) == 0 || entries[0] == nil {
+			return nil, fmt.Errorf("empty batch proof")
+		}
+		if exist := entries[0].GetExist(); exist != nil {
+			return calculateExistence(exist)
+		}
+		return CalculateRoot(&ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Nonexist{Nonexist: entries[0].GetNonexist()}})
+	default:
+		return nil, fmt.Errorf("unsupported commitment proof %T", proof.GetProof())
+	}
+}
+
+func existenceProofForKey(proof *ics23.CommitmentProof, key []byte) *ics23.ExistenceProof {
+	if exist := proof.GetExist(); exist != nil {
+		if bytes.Equal(exist.Key, key) {
+			return exist
+		}
+		return nil
+	}
+	for _, entry := range proof.GetBatch().GetEntries() {
+		if exist := entry.GetExist(); exist != nil && bytes.Equal(exist.Key, key) {
+			return exist
+		}
+	}
+	return nil
+}
+
+func nonExistenceProofForKey(proof *ics23.CommitmentProof, key []byte) *ics23.NonExistenceProof {
+	if nonExist := proof.GetNonexist(); nonExist != nil {
+		if isBetween(nonExist, key) {
+			return nonExist
+		}
+		return nil
+	}
+	for _, entry := range proof.GetBatch().GetEntries() {
+		if nonExist := entry.GetNonexist(); nonExist != nil && isBetween(nonExist, key) {
+			return nonExist
+		}
+	}
+	return nil
+}
+
+func isBetween(nonExist *ics23.NonExistenceProof, key []byte) bool {
+	if nonExist.Left != nil && bytes.Compare(nonExist.Left.Key, key) >= 0 {
+		return false
+	}
+	if nonExist.Right != nil && bytes.Compare(nonExist.Right.Key, key) <= 0 {
+		return false
+	}
+	return true
+}
+
+func verifyExistence(p *ics23.ExistenceProof, spec *ics23.ProofSpec, root, key, value []byte) error {
+	if err := checkExistenceAgainstSpec(p, spec); err != nil {
+		return err
+	}
+	if !bytes.Equal(key, p.Key) {
+		return fmt.Errorf("the proof is for the key %X, not %X", p.Key, key)
+	}
+	if !bytes.Equal(value, p.Value) {
+		return fmt.Errorf("the proof is for the value %X, not %X", p.Value, value)
+	}
+
+	calculated, err := calculateExistence(p)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, calculated) {
+		return fmt.Errorf("the proof calculates the root %X, not %X", calculated, root)
+	}
+	return nil
+}
+
+func verifyNonExistence(p *ics23.NonExistenceProof, spec *ics23.ProofSpec, root, key []byte) error {
+	if p.Left == nil && p.Right == nil {
+		return fmt.Errorf("the non-existence proof has no neighbour")
+	}
+	if p.Left != nil {
+		if err := verifyExistence(p.Left, spec, root, p.Left.Key, p.Left.Value); err != nil {
+			return fmt.Errorf("left neighbour: %s", err.Error())
+		}
+		if bytes.Compare(key, p.Left.Key) <= 0 {
+			return fmt.Errorf("the key is not right of the left neighbour")
+		}
+	}
+	if p.Right != nil {
+		if err := verifyExistence(p.Right, spec, root, p.Right.Key, p.Right.Value); err != nil {
+			return fmt.Errorf("right neighbour: %s", err.Error())
+		}
+		if bytes.Compare(key, p.Right.Key) >= 0 {
+			return fmt.Errorf("the key is not left of the right neighbour")
+		}
+	}
+
+	switch {
+	case p.Left == nil:
+		if !isLeftMost(spec.InnerSpec, p.Right.Path) {
+			return fmt.Errorf("no left neighbour, the right neighbour must be the left-most key")
+		}
+	case p.Right == nil:
+		if !isRightMost(spec.InnerSpec, p.Left.Path) {
+			return fmt.Errorf("no right neighbour, the left neighbour must be the right-most key")
+		}
+	default:
+		if !isLeftNeighbor(spec.InnerSpec, p.Left.Path, p.Right.Path) {
+			return fmt.Errorf("the left and right proofs are not neighbours")
+		}
+	}
+	return nil
+}
+
+func calculateExistence(p *ics23.ExistenceProof) ([]byte, error) {
+	if p.Leaf == nil {
+		return nil, fmt.Errorf("the existence proof has no leaf")
+	}
+	res, err := applyLeaf(p.Leaf, p.Key, p.Value)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range p.Path {
+		if res, err = applyInner(step, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func checkExistenceAgainstSpec(p *ics23.ExistenceProof, spec *ics23.ProofSpec) error {
+	if p.Leaf == nil {
+		return fmt.Errorf("the existence proof has no leaf")
+	}
+	if err := checkLeafAgainstSpec(p.Leaf, spec); err != nil {
+		return err
+	}
+	if spec.MinDepth > 0 && len(p.Path) < int(spec.MinDepth) {
+		return fmt.Errorf("the proof is %d deep, at least %d required", len(p.Path), spec.MinDepth)
+	}
+	if spec.MaxDepth > 0 && len(p.Path) > int(spec.MaxDepth) {
+		return fmt.Errorf("the proof is %d deep, at most %d allowed", len(p.Path), spec.MaxDepth)
+	}
+	for _, inner := range p.Path {
+		if err := checkInnerAgainstSpec(inner, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkLeafAgainstSpec(op *ics23.LeafOp, spec *ics23.ProofSpec) error {
+	leaf := spec.LeafSpec
+	if op.Hash != leaf.Hash {
+		return fmt.Errorf("unexpected leaf hash %s", op.Hash)
+	}
+	if op.PrehashKey != leaf.PrehashKey {
+		return fmt.Errorf("unexpected leaf key prehash %s", op.PrehashKey)
+	}
+	if op.PrehashValue != leaf.PrehashValue {
+		return fmt.Errorf("unexpected leaf value prehash %s", op.PrehashValue)
+	}
+	if op.Length != leaf.Length {
+		return fmt.Errorf("unexpected leaf length op %s", op.Length)
+	}
+	if !bytes.HasPrefix(op.Prefix, leaf.Prefix) {
+		return fmt.Errorf("the leaf prefix %X doesn't start with %X", op.Prefix, leaf.Prefix)
+	}
+	return nil
+}
+
+func checkInnerAgainstSpec(op *ics23.InnerOp, spec *ics23.ProofSpec) error {
+	inner := spec.InnerSpec
+	if op.Hash != inner.Hash {
+		return fmt.Errorf("unexpected inner hash %s", op.Hash)
+	}
+	// an inner node must not be mistaken for a leaf
+	if bytes.HasPrefix(op.Prefix, spec.LeafSpec.Prefix) {
+		return fmt.Errorf("the inner prefix starts with the leaf prefix")
+	}
+	if len(op.Prefix) < int(inner.MinPrefixLength) {
+		return fmt.Errorf("the inner prefix is too short")
+	}
+	maxLeftChildBytes := (len(inner.ChildOrder) - 1) * int(inner.ChildSize)
+	if len(op.Prefix) > int(inner.MaxPrefixLength)+maxLeftChildBytes {
+		return fmt.Errorf("the inner prefix is too long")
+	}
+	if inner.ChildSize > 0 && len(op.Suffix)%int(inner.ChildSize) != 0 {
+		return fmt.Errorf("the inner suffix is not made of children")
+	}
+	return nil
+}
+
+func applyLeaf(op *ics23.LeafOp, key, value []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("the leaf needs a key")
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("the leaf needs a value")
+	}
+	pkey, err := prepareLeafData(op.PrehashKey, op.Length, key)
+	if err != nil {
+		return nil, err
+	}
+	pvalue, err := prepareLeafData(op.PrehashValue, op.Length, value)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append(append(append([]byte{}, op.Prefix...), pkey...), pvalue...)
+	return doHash(op.Hash, data)
+}
+
+func applyInner(op *ics23.InnerOp, child []byte) ([]byte, error) {
+	if len(child) == 0 {
+		return nil, fmt.Errorf("the inner node needs a child")
+	}
+	preimage := append(append(append([]byte{}, op.Prefix...), child...), op.Suffix...)
+	return doHash(op.Hash, preimage)
+}
+
+func prepareLeafData(hashOp ics23.HashOp, lengthOp ics23.LengthOp, data []byte) ([]byte, error) {
+	if hashOp != ics23.HashOp_NO_HASH {
+		var err error
+		if data, err = doHash(hashOp, data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch lengthOp {
+	case ics23.LengthOp_NO_PREFIX:
+		return data, nil
+	case ics23.LengthOp_VAR_PROTO:
+		prefix := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(prefix, uint64(len(data)))
+		return append(prefix[:n], data...), nil
+	case ics23.LengthOp_FIXED32_LITTLE:
+		prefix := make([]byte, 4)
+		binary.LittleEndian.PutUint32(prefix, uint32(len(data)))
+		return append(prefix, data...), nil
+	case ics23.LengthOp_REQUIRE_32_BYTES:
+		if len(data) != 32 {
+			return nil, fmt.Errorf("the data is %d bytes, 32 required", len(data))
+		}
+		return data, nil
+	case ics23.LengthOp_REQUIRE_64_BYTES:
+		if len(data) != 64 {
+			return nil, fmt.Errorf("the data is %d bytes, 64 required", len(data))
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported length op %s", lengthOp)
+	}
+}
+
+func doHash(hashOp ics23.HashOp, preimage []byte) ([]byte, error) {
+	var h hash.Hash
+	switch hashOp {
+	case ics23.HashOp_SHA256:
+		h = sha256.New()
+	case ics23.HashOp_SHA512:
+		h = sha512.New()
+	case ics23.HashOp_KECCAK:
+		h = sha3.NewLegacyKeccak256()
+	case ics23.HashOp_RIPEMD160:
+		h = ripemd160.New()
+	case ics23.HashOp_BITCOIN:
+		sum := sha256.Sum256(preimage)
+		h, preimage = ripemd160.New(), sum[:]
+	default:
+		return nil, fmt.Errorf("unsupported hash op %s", hashOp)
+	}
+	h.Write(preimage)
+	return h.Sum(nil), nil
+}
+
+// isLeftMost returns true when every step of the path is the left-most child
+func isLeftMost(spec *ics23.InnerSpec, path []*ics23.InnerOp) bool {
+	minPrefix, maxPrefix, suffix := getPadding(spec, 0)
+	for _, step := range path {
+		if !hasPadding(step, minPrefix, maxPrefix, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRightMost returns true when every step of the path is the right-most non-empty child
+func isRightMost(spec *ics23.InnerSpec, path []*ics23.InnerOp) bool {
+	minPrefix, maxPrefix, suffix := getPadding(spec, int32(len(spec.ChildOrder)-1))
+	for _, step := range path {
+		if !hasPadding(step, minPrefix, maxPrefix, suffix) && !rightBranchesAreEmpty(spec, step) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLeftNeighbor returns true when the paths, from the leaves to the root, lead to adjacent leaves
+func isLeftNeighbor(spec *ics23.InnerSpec, left, right []*ics23.InnerOp) bool {
+	// skip the common ancestors from the root
+	l, r := len(left)-1, len(right)-1
+	for l >= 0 && r >= 0 && bytes.Equal(left[l].Prefix, right[r].Prefix) && bytes.Equal(left[l].Suffix, right[r].Suffix) {
+		l--
+		r--
+	}
+	if l < 0 || r < 0 {
+		return false
+	}
+
+	// the paths split at adjacent children, then the left one goes right-most and the right one left-most
+	if !isLeftStep(spec, left[l], right[r]) {
+		return false
+	}
+	return isRightMost(spec, left[:l]) && isLeftMost(spec, right[:r])
+}
+
+func isLeftStep(spec *ics23.InnerSpec, left, right *ics23.InnerOp) bool {
+	leftIdx, err := orderFromPadding(spec, left)
+	if err != nil {
+		return false
+	}
+	rightIdx, err := orderFromPadding(spec, right)
+	if err != nil {
+		return false
+	}
+	return rightIdx == leftIdx+1
+}
+
+func hasPadding(op *ics23.InnerOp, minPrefix, maxPrefix, suffix int) bool {
+	return len(op.Prefix) >= minPrefix && len(op.Prefix) <= maxPrefix && len(op.Suffix) == suffix
+}
+
+// getPadding returns the prefix and suffix lengths of an inner node whose child is at the branch
+func getPadding(spec *ics23.InnerSpec, branch int32) (minPrefix, maxPrefix, suffix int) {
+	idx := getPosition(spec.ChildOrder, branch)
+	prefix := idx * int(spec.ChildSize)
+	minPrefix = prefix + int(spec.MinPrefixLength)
+	maxPrefix = prefix + int(spec.MaxPrefixLength)
+	suffix = (len(spec.ChildOrder) - 1 - idx) * int(spec.ChildSize)
+	return
+}
+
+func getPosition(order []int32, branch int32) int {
+	for i, item := range order {
+		if item == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+func orderFromPadding(spec *ics23.InnerSpec, op *ics23.InnerOp) (int32, error) {
+	for branch := int32(0); branch < int32(len(spec.ChildOrder)); branch++ {
+		minPrefix, maxPrefix, suffix := getPadding(spec, branch)
+		if hasPadding(op, minPrefix, maxPrefix, suffix) {
+			return branch, nil
+		}
+	}
+	return 0, fmt.Errorf("the inner node matches no branch")
+}
+
+// rightBranchesAreEmpty returns true when the children right of the path are all the empty child
+func rightBranchesAreEmpty(spec *ics23.InnerSpec, op *ics23.InnerOp) bool {
+	if len(spec.EmptyChild) == 0 {
+		return false
+	}
+	idx, err := orderFromPadding(spec, op)
+	if err != nil {
+		return false
+	}
+	rightBranches := len(spec.ChildOrder) - 1 - int(idx)
+	if rightBranches == 0 || len(op.Suffix) != rightBranches*int(spec.ChildSize) {
+		return false
+	}
+	for i := 0; i < rightBranches; i++ {
+		from := i * int(spec.ChildSize)
+		if !bytes.Equal(spec.EmptyChild, op.Suffix[from:from+int(spec.ChildSize)]) {
+			return false
+		}
+	}
+	return true
+}
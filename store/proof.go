@@ -0,0 +1,117 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmcrypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+
+	ics23 "github.com/irisnet/core-sdk-go/third_party/github.com/confio/ics23/go"
+)
+
+const (
+	// ProofOpIAVLCommitment is the proof op type of the key of an IAVL substore
+	ProofOpIAVLCommitment = "ics23:iavl"
+	// ProofOpSimpleMerkleCommitment is the proof op type of the substore in the multistore
+	ProofOpSimpleMerkleCommitment = "ics23:simple"
+)
+
+// CommitmentOp implements merkle.ProofOperator with an ICS23 commitment proof. Given the value of the
+// key, or no value to prove its absence, it returns the root of the tree.
+type CommitmentOp struct {
+	Type  string
+	Spec  *ics23.ProofSpec
+	Key   []byte
+	Proof *ics23.CommitmentProof
+}
+
+var _ merkle.ProofOperator = CommitmentOp{}
+
+// NewIavlCommitmentOp returns the proof operator of an IAVL commitment proof
+func NewIavlCommitmentOp(key []byte, proof *ics23.CommitmentProof) CommitmentOp {
+	return CommitmentOp{Type: ProofOpIAVLCommitment, Spec: IavlSpec, Key: key, Proof: proof}
+}
+
+// NewSimpleMerkleCommitmentOp returns the proof operator of a simple merkle commitment proof
+func NewSimpleMerkleCommitmentOp(key []byte, proof *ics23.CommitmentProof) CommitmentOp {
+	return CommitmentOp{Type: ProofOpSimpleMerkleCommitment, Spec: TendermintSpec, Key: key, Proof: proof}
+}
+
+// CommitmentOpDecoder decodes the ProofOps of the ics23:iavl and ics23:simple types
+func CommitmentOpDecoder(pop tmcrypto.ProofOp) (merkle.ProofOperator, error) {
+	var spec *ics23.ProofSpec
+	switch pop.Type {
+	case ProofOpIAVLCommitment:
+		spec = IavlSpec
+	case ProofOpSimpleMerkleCommitment:
+		spec = TendermintSpec
+	default:
+		return nil, fmt.Errorf("unexpected ProofOp.Type; got %s, want supported ics23 subtype", pop.Type)
+	}
+
+	proof := &ics23.CommitmentProof{}
+	if err := proof.Unmarshal(pop.Data); err != nil {
+		return nil, err
+	}
+	return CommitmentOp{Type: pop.Type, Spec: spec, Key: pop.Key, Proof: proof}, nil
+}
+
+func (op CommitmentOp) GetKey() []byte {
+	return op.Key
+}
+
+// Run proves the existence of the value args[0] under the key, or the absence of the key when args
+// is empty, and returns the root calculated from the proof
+func (op CommitmentOp) Run(args [][]byte) ([][]byte, error) {
+	root, err := CalculateRoot(op.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("could not calculate root for proof: %s", err.Error())
+	}
+
+	switch len(args) {
+	case 0:
+		if err := VerifyNonMembership(op.Spec, root, op.Proof, op.Key); err != nil {
+			return nil, fmt.Errorf("could not prove the absence of the key %X: %s", op.Key, err.Error())
+		}
+	case 1:
+		if err := VerifyMembership(op.Spec, root, op.Proof, op.Key, args[0]); err != nil {
+			return nil, fmt.Errorf("could not prove the value of the key %X: %s", op.Key, err.Error())
+		}
+	default:
+		return nil, fmt.Errorf("args must be length 0 or 1, got: %d", len(args))
+	}
+	return [][]byte{root}, nil
+}
+
+func (op CommitmentOp) ProofOp() tmcrypto.ProofOp {
+	bz, err := op.Proof.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return tmcrypto.ProofOp{Type: op.Type, Key: op.Key, Data: bz}
+}
+
+// DefaultProofRuntime returns the ProofRuntime of the proofs of the multistore
+func DefaultProofRuntime() *merkle.ProofRuntime {
+	prt := merkle.NewProofRuntime()
+	prt.RegisterOpDecoder(ProofOpIAVLCommitment, CommitmentOpDecoder)
+	prt.RegisterOpDecoder(ProofOpSimpleMerkleCommitment, CommitmentOpDecoder)
+	return prt
+}
+
+// VerifyStoreQuery verifies the proof of a query of the key of a substore against the app hash
+// committing to the state of the query height. An empty value is verified as absent.
+func VerifyStoreQuery(proof *tmcrypto.ProofOps, appHash []byte, storeName string, key, value []byte) error {
+	if proof == nil || len(proof.Ops) == 0 {
+		return fmt.Errorf("the query has no proof")
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+	if len(value) == 0 {
+		return DefaultProofRuntime().VerifyAbsence(proof, appHash, keyPath)
+	}
+	return DefaultProofRuntime().VerifyValue(proof, appHash, keyPath, value)
+}
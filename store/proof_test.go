@@ -0,0 +1,242 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmcrypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+
+	ics23 "github.com/irisnet/core-sdk-go/third_party/github.com/confio/ics23/go"
+)
+
+// testTree is a balanced tree of sorted keys hashed like IAVL or the simple merkle tree
+type testTree struct {
+	spec        *ics23.ProofSpec
+	leafPrefix  []byte
+	innerPrefix []byte
+	keys        [][]byte
+	values      [][]byte
+	levels      [][][]byte
+}
+
+func newTestTree(spec *ics23.ProofSpec, leafPrefix, innerPrefix []byte, keys []string, values [][]byte) *testTree {
+	tree := &testTree{spec: spec, leafPrefix: leafPrefix, innerPrefix: innerPrefix, values: values}
+	var leaves [][]byte
+	for i, key := range keys {
+		tree.keys = append(tree.keys, []byte(key))
+		leaves = append(leaves, tree.leafHash([]byte(key), values[i]))
+	}
+
+	tree.levels = [][][]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			preimage := append(append(append([]byte{}, innerPrefix...), tree.child(level[i])...), tree.child(level[i+1])...)
+			h := sha256.Sum256(preimage)
+			next = append(next, h[:])
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree
+}
+
+func (tree *testTree) leafHash(key, value []byte) []byte {
+	valueHash := sha256.Sum256(value)
+	preimage := append(append([]byte{}, tree.leafPrefix...), varint(len(key))...)
+	preimage = append(append(preimage, key...), varint(len(valueHash))...)
+	h := sha256.Sum256(append(preimage, valueHash[:]...))
+	return h[:]
+}
+
+// child returns the encoding of a child hash in its parent, IAVL length-prefixes it
+func (tree *testTree) child(h []byte) []byte {
+	if tree.spec.InnerSpec.ChildSize == 33 {
+		return append([]byte{byte(len(h))}, h...)
+	}
+	return h
+}
+
+func (tree *testTree) root() []byte {
+	return tree.levels[len(tree.levels)-1][0]
+}
+
+func (tree *testTree) exist(i int) *ics23.ExistenceProof {
+	proof := &ics23.ExistenceProof{
+		Key:   tree.keys[i],
+		Value: tree.values[i],
+		Leaf: &ics23.LeafOp{
+			Hash:         ics23.HashOp_SHA256,
+			PrehashValue: ics23.HashOp_SHA256,
+			Length:       ics23.LengthOp_VAR_PROTO,
+			Prefix:       tree.leafPrefix,
+		},
+	}
+
+	// the encoding of the proven child without its hash, the length byte for IAVL
+	lengthByte := tree.child(make([]byte, sha256.Size))[:tree.spec.InnerSpec.ChildSize-sha256.Size]
+	for idx, level := 0, tree.levels[:len(tree.levels)-1]; idx < len(level); idx++ {
+		n := i >> idx
+		op := &ics23.InnerOp{Hash: ics23.HashOp_SHA256}
+		if n%2 == 0 {
+			op.Prefix = append(append([]byte{}, tree.innerPrefix...), lengthByte...)
+			op.Suffix = tree.child(level[idx][n+1])
+		} else {
+			op.Prefix = append(append(append([]byte{}, tree.innerPrefix...), tree.child(level[idx][n-1])...), lengthByte...)
+		}
+		proof.Path = append(proof.Path, op)
+	}
+	return proof
+}
+
+func (tree *testTree) existProof(i int) *ics23.CommitmentProof {
+	return &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Exist{Exist: tree.exist(i)}}
+}
+
+// nonExistProof proves the absence of the key between the leaves left and right, -1 for none
+func (tree *testTree) nonExistProof(key string, left, right int) *ics23.CommitmentProof {
+	nonExist := &ics23.NonExistenceProof{Key: []byte(key)}
+	if left >= 0 {
+		nonExist.Left = tree.exist(left)
+	}
+	if right >= 0 {
+		nonExist.Right = tree.exist(right)
+	}
+	return &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Nonexist{Nonexist: nonExist}}
+}
+
+func varint(n int) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutUvarint(buf, uint64(n))]
+}
+
+func TestVerifyStoreQuery(t *testing.T) {
+	// the bank store is an IAVL tree, its root is a leaf of the multistore
+	bank := newTestTree(IavlSpec, []byte{0, 2, 2}, []byte{2, 4, 2},
+		[]string{"a", "c", "e", "g"},
+		[][]byte{[]byte("1"), []byte("2"), []byte("3"), []byte("4")},
+	)
+	other := sha256.Sum256([]byte("other"))
+	multistore := newTestTree(TendermintSpec, []byte{0}, []byte{1},
+		[]string{"acc", "bank", "ibc", "staking"},
+		[][]byte{other[:], bank.root(), other[:], other[:]},
+	)
+	appHash := multistore.root()
+
+	proofOps := func(key string, proof *ics23.CommitmentProof) *tmcrypto.ProofOps {
+		return &tmcrypto.ProofOps{Ops: []tmcrypto.ProofOp{
+			NewIavlCommitmentOp([]byte(key), proof).ProofOp(),
+			NewSimpleMerkleCommitmentOp([]byte("bank"), multistore.existProof(1)).ProofOp(),
+		}}
+	}
+
+	t.Run("existence", func(t *testing.T) {
+		for i, key := range []string{"a", "c", "e", "g"} {
+			require.NoError(t, VerifyStoreQuery(proofOps(key, bank.existProof(i)), appHash, "bank", []byte(key), bank.values[i]))
+		}
+
+		proof := proofOps("c", bank.existProof(1))
+		require.Error(t, VerifyStoreQuery(proof, appHash, "bank", []byte("c"), []byte("3")))
+		require.Error(t, VerifyStoreQuery(proof, appHash, "acc", []byte("c"), []byte("2")))
+		require.Error(t, VerifyStoreQuery(proof, bank.root(), "bank", []byte("c"), []byte("2")))
+		require.Error(t, VerifyStoreQuery(proof, appHash, "bank", []byte("c"), nil))
+		require.Error(t, VerifyStoreQuery(nil, appHash, "bank", []byte("c"), []byte("2")))
+	})
+
+	t.Run("absence", func(t *testing.T) {
+		require.NoError(t, VerifyStoreQuery(proofOps("d", bank.nonExistProof("d", 1, 2)), appHash, "bank", []byte("d"), nil))
+		require.NoError(t, VerifyStoreQuery(proofOps("b", bank.nonExistProof("b", 0, 1)), appHash, "bank", []byte("b"), nil))
+		require.NoError(t, VerifyStoreQuery(proofOps("0", bank.nonExistProof("0", -1, 0)), appHash, "bank", []byte("0"), nil))
+		require.NoError(t, VerifyStoreQuery(proofOps("z", bank.nonExistProof("z", 3, -1)), appHash, "bank", []byte("z"), nil))
+
+		// the neighbours skip the key c
+		require.Error(t, VerifyStoreQuery(proofOps("b", bank.nonExistProof("b", 0, 2)), appHash, "bank", []byte("b"), nil))
+		// the key g lies between the only neighbour and z
+		require.Error(t, VerifyStoreQuery(proofOps("z", bank.nonExistProof("z", 2, -1)), appHash, "bank", []byte("z"), nil))
+		// the key c exists
+		require.Error(t, VerifyStoreQuery(proofOps("c", bank.nonExistProof("c", 0, 2)), appHash, "bank", []byte("c"), nil))
+		// an absence proof doesn't prove a value
+		require.Error(t, VerifyStoreQuery(proofOps("d", bank.nonExistProof("d", 1, 2)), appHash, "bank", []byte("d"), []byte("2")))
+	})
+}
+
+// proofVectors are proofs of queries of a cosmos-sdk multistore, as returned by the abci queries of a node
+type proofVectors struct {
+	Height  int64  `json:"height"`
+	AppHash string `json:"app_hash"`
+	Vectors []struct {
+		Store string `json:"store"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Proof []struct {
+			Type string `json:"type"`
+			Key  string `json:"key"`
+			Data string `json:"data"`
+		} `json:"proof"`
+	} `json:"vectors"`
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	bz, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
+
+func TestVerifyStoreQueryVectors(t *testing.T) {
+	bz, err := ioutil.ReadFile(filepath.Join("testdata", "multistore_proofs.json"))
+	require.NoError(t, err)
+	var vectors proofVectors
+	require.NoError(t, json.Unmarshal(bz, &vectors))
+	appHash := fromHex(t, vectors.AppHash)
+
+	var absent, present int
+	for _, v := range vectors.Vectors {
+		key, value := fromHex(t, v.Key), fromHex(t, v.Value)
+		proof := &tmcrypto.ProofOps{}
+		for _, op := range v.Proof {
+			proof.Ops = append(proof.Ops, tmcrypto.ProofOp{Type: op.Type, Key: fromHex(t, op.Key), Data: fromHex(t, op.Data)})
+		}
+
+		require.NoError(t, VerifyStoreQuery(proof, appHash, v.Store, key, value), "%s/%s", v.Store, key)
+
+		tampered := append([]byte{}, appHash...)
+		tampered[0] ^= 1
+		require.Error(t, VerifyStoreQuery(proof, tampered, v.Store, key, value))
+		require.Error(t, VerifyStoreQuery(proof, appHash, "acc", key, value))
+		if len(value) == 0 {
+			absent++
+			require.Error(t, VerifyStoreQuery(proof, appHash, v.Store, key, []byte("value")))
+			continue
+		}
+		present++
+		require.Error(t, VerifyStoreQuery(proof, appHash, v.Store, key, append(value, '!')))
+		require.Error(t, VerifyStoreQuery(proof, appHash, v.Store, key, nil))
+	}
+	require.NotZero(t, absent)
+	require.NotZero(t, present)
+}
+
+func TestCommitmentOpDecoder(t *testing.T) {
+	tree := newTestTree(TendermintSpec, []byte{0}, []byte{1},
+		[]string{"a", "b"},
+		[][]byte{[]byte("1"), []byte("2")},
+	)
+	op := NewSimpleMerkleCommitmentOp([]byte("a"), tree.existProof(0))
+
+	decoded, err := CommitmentOpDecoder(op.ProofOp())
+	require.NoError(t, err)
+	require.Equal(t, op.ProofOp(), decoded.ProofOp())
+
+	root, err := decoded.Run([][]byte{[]byte("1")})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{tree.root()}, root)
+
+	_, err = CommitmentOpDecoder(tmcrypto.ProofOp{Type: "iavl:v"})
+	require.Error(t, err)
+}
@@ -6,6 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
 
 	"github.com/irisnet/core-sdk-go/common/bech32"
 )
@@ -39,6 +42,21 @@ func ValidateAccAddress(address string) Error {
 	return nil
 }
 
+// AccAddressFromHex creates an AccAddress from a 0x hex address, the EIP-55 checksum is
+// verified when the address is mixed case
+func AccAddressFromHex(address string) (AccAddress, Error) {
+	if !ethcommon.IsHexAddress(address) {
+		return nil, Wrapf("invalid hex address %s", address)
+	}
+	addr := ethcommon.HexToAddress(address)
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if trimmed != strings.ToLower(trimmed) && trimmed != strings.ToUpper(trimmed) &&
+		addr.Hex() != "0x"+trimmed {
+		return nil, Wrapf("invalid checksum of hex address %s", address)
+	}
+	return AccAddress(addr.Bytes()), nil
+}
+
 func MustAccAddressFromBech32(address string) AccAddress {
 	addr, err := AccAddressFromBech32(address)
 	if err != nil {
@@ -121,6 +139,15 @@ func (aa AccAddress) Bytes() []byte {
 	return aa
 }
 
+// EthAddress returns the 0x hex form of the address with the EIP-55 checksum, it is the address
+// MetaMask shows for an eth_secp256k1 key
+func (aa AccAddress) EthAddress() string {
+	if len(aa) != AddrLen {
+		return ""
+	}
+	return ethcommon.BytesToAddress(aa).Hex()
+}
+
 // ----------------------------------------------------------------------------
 // validator operator
 // ----------------------------------------------------------------------------
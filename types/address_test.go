@@ -23,3 +23,23 @@ func TestGetFromBech32(t *testing.T) {
 	require.NoError(t, err)
 	fmt.Println(addr)
 }
+
+func TestEthAddress(t *testing.T) {
+	// EIP-55 test vector
+	const ethAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+	addr, err := AccAddressFromHex(ethAddress)
+	require.NoError(t, err)
+	require.Equal(t, ethAddress, addr.EthAddress())
+
+	bech32Addr, err2 := AccAddressFromBech32(addr.String())
+	require.NoError(t, err2)
+	require.Equal(t, ethAddress, bech32Addr.EthAddress())
+
+	_, err = AccAddressFromHex("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.NoError(t, err)
+	_, err = AccAddressFromHex("0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.Error(t, err)
+	_, err = AccAddressFromHex("5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA")
+	require.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// MsgSignDataType is the amino type of the ADR-036 message wrapping off-chain data
+const MsgSignDataType = "sign/MsgSignData"
+
+// ArbitrarySignature is the signature of off-chain data, it has the JSON layout of the
+// StdSignature returned by Keplr's signArbitrary
+type ArbitrarySignature struct {
+	PubKey    AminoPubKey `json:"pub_key" yaml:"pub_key"`
+	Signature []byte      `json:"signature" yaml:"signature"`
+}
+
+// AminoPubKey is the amino JSON of a public key, e.g. {"type":"tendermint/PubKeySecp256k1","value":"..."}
+type AminoPubKey struct {
+	Type  string `json:"type" yaml:"type"`
+	Value []byte `json:"value" yaml:"value"`
+}
+
+type adr036SignDoc struct {
+	AccountNumber string      `json:"account_number"`
+	ChainID       string      `json:"chain_id"`
+	Fee           adr036Fee   `json:"fee"`
+	Memo          string      `json:"memo"`
+	Msgs          []adr036Msg `json:"msgs"`
+	Sequence      string      `json:"sequence"`
+}
+
+type adr036Fee struct {
+	Amount []Coin `json:"amount"`
+	Gas    string `json:"gas"`
+}
+
+type adr036Msg struct {
+	Type  string      `json:"type"`
+	Value msgSignData `json:"value"`
+}
+
+type msgSignData struct {
+	Data   []byte `json:"data"`
+	Signer string `json:"signer"`
+}
+
+// ADR036SignBytes returns the bytes signed for off-chain data as defined by ADR-036: the sorted
+// amino JSON of a sign doc holding a single sign/MsgSignData, with an empty chain id, a zero fee,
+// an account number and a sequence of 0
+func ADR036SignBytes(signer string, data []byte) []byte {
+	doc := adr036SignDoc{
+		AccountNumber: "0",
+		Fee:           adr036Fee{Amount: []Coin{}, Gas: "0"},
+		Msgs: []adr036Msg{{
+			Type:  MsgSignDataType,
+			Value: msgSignData{Data: data, Signer: signer},
+		}},
+		Sequence: "0",
+	}
+
+	bz, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	return MustSortJSON(bz)
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"context"
+	"time"
+
 	grpc1 "github.com/gogo/protobuf/grpc"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
@@ -12,6 +15,8 @@ type TxManager interface {
 	BuildAndSend(msg []Msg, baseTx BaseTx) (ResultTx, Error)
 	BuildAndSign(msg []Msg, baseTx BaseTx) ([]byte, Error)
 	BuildTxHash(msg []Msg, baseTx BaseTx) (string, Error)
+	// BuildUnsignedTx returns the JSON of the tx without signing it, e.g. for a watch-only key
+	BuildUnsignedTx(msg []Msg, baseTx BaseTx) ([]byte, Error)
 	BuildAndSendWithAccount(addr string, accountNumber, sequence uint64, msg []Msg, baseTx BaseTx) (ResultTx, Error)
 	BuildAndSignWithAccount(addr string, accountNumber, sequence uint64, msg []Msg, baseTx BaseTx) ([]byte, Error)
 }
@@ -22,6 +27,10 @@ type Queries interface {
 	TmQuery
 }
 
+// GRPCBlockHeightHeader is the gRPC metadata of the height a query is served at, the node reads it
+// from the request and sets it in the response
+const GRPCBlockHeightHeader = "x-cosmos-block-height"
+
 type GRPCClient interface {
 	GenConn() (grpc1.ClientConn, error)
 }
@@ -34,6 +43,7 @@ type StoreQuery interface {
 	QueryWithResponse(path string, data interface{}, result Response) error
 	Query(path string, data interface{}) ([]byte, error)
 	QueryStore(key HexBytes, storeName string, height int64, prove bool) (abci.ResponseQuery, error)
+	VerifiedQueryStore(key HexBytes, storeName string, height int64) (abci.ResponseQuery, error)
 }
 
 type AccountQuery interface {
@@ -48,7 +58,17 @@ type CacheManager interface {
 type TmQuery interface {
 	QueryTx(hash string) (ResultQueryTx, error)
 	QueryTxs(builder *EventQueryBuilder, page, size *int) (ResultSearchTxs, error)
+	IterateTxs(builder *EventQueryBuilder, opts TxSearchOptions) (TxIterator, error)
+	QueryTxHistory(address string, req TxHistoryRequest) (TxHistory, error)
 	QueryBlock(height int64) (BlockDetail, error)
+	// TrustedHeader returns the header at the height, the latest for 0, verified by the light client
+	TrustedHeader(height int64) (Header, error)
+}
+
+type MempoolQuery interface {
+	QueryUnconfirmedTxs(filter MempoolFilter, limit int) (ResultUnconfirmedTxs, error)
+	QueryNumUnconfirmedTxs() (ResultUnconfirmedTxs, error)
+	SubscribePendingTx(filter MempoolFilter, interval time.Duration, handler EventPendingTxHandler) (MempoolSubscription, Error)
 }
 
 type TokenManager interface {
@@ -63,13 +83,33 @@ type Logger interface {
 	SetLogger(log.Logger)
 }
 
+// HeightQuery pins the queries to a past height, e.g. to read balances as of a block
+type HeightQuery interface {
+	// WithHeight returns a copy of the client whose gRPC and ABCI queries are served at the height,
+	// it can't build txs when pinned to a past height. A negative height is refused.
+	WithHeight(height int64) (BaseClient, error)
+	// ServedHeight returns the height the last query of a client of WithHeight was served at, the
+	// last writer's among goroutines sharing a client pinned to 0
+	ServedHeight() int64
+}
+
+// DynamicQuery invokes the gRPC query methods without their generated clients
+type DynamicQuery interface {
+	// GRPCQuery invokes the method, e.g. "/cosmos.bank.v1beta1.Query/Balance", with the JSON of its
+	// request and returns the JSON of its response
+	GRPCQuery(ctx context.Context, method string, jsonRequest []byte) ([]byte, error)
+}
+
 type BaseClient interface {
 	TokenManager
 	TxManager
 	Queries
+	MempoolQuery
 	TmClient
 	Logger
 	GRPCClient
 	KeyManager
 	CacheManager
+	HeightQuery
+	DynamicQuery
 }
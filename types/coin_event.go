@@ -0,0 +1,174 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Event types and attribute keys emitted by the bank module whenever balances change
+var (
+	EventTypeCoinSpent    = "coin_spent"
+	EventTypeCoinReceived = "coin_received"
+	EventTypeCoinbase     = "coinbase"
+	EventTypeBurn         = "burn"
+
+	AttributeKeySpender  = "spender"
+	AttributeKeyReceiver = "receiver"
+	AttributeKeyMinter   = "minter"
+	AttributeKeyBurner   = "burner"
+)
+
+// CoinDelta is a signed balance change of a single denom
+type CoinDelta struct {
+	Denom  string `json:"denom"`
+	Amount Int    `json:"amount"`
+}
+
+func (d CoinDelta) String() string {
+	return fmt.Sprintf("%v%s", d.Amount, d.Denom)
+}
+
+// CoinDeltas is a set of signed balance changes sorted by denom
+type CoinDeltas []CoinDelta
+
+// AmountOf returns the delta of the given denom
+func (ds CoinDeltas) AmountOf(denom string) Int {
+	for _, d := range ds {
+		if d.Denom == denom {
+			return d.Amount
+		}
+	}
+	return ZeroInt()
+}
+
+// IsAnyNegative returns true if any denom decreased
+func (ds CoinDeltas) IsAnyNegative() bool {
+	for _, d := range ds {
+		if d.Amount.IsNegative() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAnyPositive returns true if any denom increased
+func (ds CoinDeltas) IsAnyPositive() bool {
+	for _, d := range ds {
+		if d.Amount.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// CoinMovement is a single credit or debit of an address extracted from bank events
+type CoinMovement struct {
+	Address string `json:"address"`
+	Coins   Coins  `json:"coins"`
+	// Credit is true for coin_received/coinbase, false for coin_spent/burn
+	Credit bool `json:"credit"`
+}
+
+// ParseCoinMovements extracts the credits and debits recorded in coin_received and coin_spent events.
+// The attributes of flattened events keep their emission order, so every amount belongs to the
+// receiver/spender immediately preceding it.
+func ParseCoinMovements(events StringEvents) ([]CoinMovement, error) {
+	return parseCoinMovements(events, map[string]coinEventKind{
+		EventTypeCoinReceived: {AttributeKeyReceiver, true},
+		EventTypeCoinSpent:    {AttributeKeySpender, false},
+	})
+}
+
+// ParseBalanceMovements extracts every balance change recorded in the events. The bank module emits
+// coin_received/coin_spent alongside coinbase/burn, so coinbase and burn are only used when the events
+// carry no coin_received/coin_spent, otherwise mints and burns would be counted twice.
+func ParseBalanceMovements(events StringEvents) ([]CoinMovement, error) {
+	movements, err := ParseCoinMovements(events)
+	if err != nil || len(movements) > 0 {
+		return movements, err
+	}
+	return parseCoinMovements(events, map[string]coinEventKind{
+		EventTypeCoinbase: {AttributeKeyMinter, true},
+		EventTypeBurn:     {AttributeKeyBurner, false},
+	})
+}
+
+type coinEventKind struct {
+	addrKey string
+	credit  bool
+}
+
+func parseCoinMovements(events StringEvents, kinds map[string]coinEventKind) ([]CoinMovement, error) {
+	var movements []CoinMovement
+	for _, e := range events {
+		kind, ok := kinds[e.Type]
+		if !ok {
+			continue
+		}
+
+		var address string
+		for _, attr := range e.Attributes {
+			switch attr.Key {
+			case kind.addrKey:
+				address = attr.Value
+			case AttributeKeyAmount:
+				coins, err := ParseCoins(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				if len(address) == 0 || coins.Empty() {
+					continue
+				}
+				movements = append(movements, CoinMovement{
+					Address: address,
+					Coins:   coins,
+					Credit:  kind.credit,
+				})
+				address = ""
+			}
+		}
+	}
+	return movements, nil
+}
+
+// ParseCoinDeltas computes the net balance change of the address from coin_received and coin_spent events
+func ParseCoinDeltas(address string, events StringEvents) (CoinDeltas, error) {
+	movements, err := ParseCoinMovements(events)
+	if err != nil {
+		return nil, err
+	}
+	return SumCoinMovements(address, movements), nil
+}
+
+// SumCoinMovements nets the movements of the address per denom, zero deltas are dropped
+func SumCoinMovements(address string, movements []CoinMovement) CoinDeltas {
+	amounts := make(map[string]Int)
+	for _, m := range movements {
+		if m.Address != address {
+			continue
+		}
+		for _, coin := range m.Coins {
+			amt, ok := amounts[coin.Denom]
+			if !ok {
+				amt = ZeroInt()
+			}
+			if m.Credit {
+				amounts[coin.Denom] = amt.Add(coin.Amount)
+			} else {
+				amounts[coin.Denom] = amt.Sub(coin.Amount)
+			}
+		}
+	}
+
+	var deltas CoinDeltas
+	for denom, amt := range amounts {
+		if amt.IsZero() {
+			continue
+		}
+		deltas = append(deltas, CoinDelta{Denom: denom, Amount: amt})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Denom < deltas[j].Denom
+	})
+	return deltas
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/x509"
 	"fmt"
 	"os"
+	"time"
 
+	tmmath "github.com/tendermint/tendermint/libs/math"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
@@ -26,6 +28,11 @@ const (
 	BIP44Prefix          = "44'/118'/"
 	PartialPath          = "0'/0/0"
 	FullPath             = "m/" + BIP44Prefix + PartialPath
+
+	defaultTrustPeriod = 168 * time.Hour
+
+	defaultQueryCacheCapacity = 1000
+	defaultParamsExpiration   = 10 * time.Minute
 )
 
 type ClientConfig struct {
@@ -75,6 +82,11 @@ type ClientConfig struct {
 
 	KeyManager crypto.KeyManager
 
+	// Signer replaces the key manager backed by KeyDAO to sign, e.g. with a remote signer. The keys are
+	// then managed by the signer, unless it is a KeyManager. The optional features of the keys client,
+	// e.g. the listing of a KeyLister, are available when the signer implements them.
+	Signer Signer
+
 	TxSizeLimit uint64
 
 	// BIP44 path
@@ -91,6 +103,47 @@ type ClientConfig struct {
 
 	FeeGranter AccAddress
 	FeePayer   AccAddress
+
+	// LightClient verifies the headers of RPCAddr, nil to trust the node
+	LightClient *LightClientConfig
+
+	// RPCBatchSize is the maximum number of calls per json-rpc batch request
+	RPCBatchSize int
+
+	// QueryCache caches the responses of the gRPC and ABCI queries per height, nil to disable it.
+	// The account queries building the txs are not cached.
+	QueryCache *QueryCacheConfig
+}
+
+// LightClientConfig configures the light client verifying the headers served by the rpc node
+type LightClientConfig struct {
+	// the header trusted to start from, e.g. read from a block explorer or a validator
+	TrustHeight int64
+	TrustHash   HexBytes
+
+	// TrustPeriod must be shorter than the unbonding period of the chain
+	TrustPeriod time.Duration
+
+	// TrustLevel is the fraction of the trusted validators which must have signed a skipped header
+	TrustLevel tmmath.Fraction
+
+	// Sequential verifies every header instead of skipping to the requested one
+	Sequential bool
+
+	// Witnesses are the rpc addresses of the nodes cross-checking the headers to detect forks
+	Witnesses []string
+
+	// DBPath of the trust store, the trusted headers are only kept in memory when empty
+	DBPath string
+}
+
+// QueryCacheConfig configures the cache of the query responses
+type QueryCacheConfig struct {
+	// Capacity is the number of responses kept
+	Capacity int
+
+	// ParamsExpiration is how long the params of the latest height are kept across the blocks
+	ParamsExpiration time.Duration
 }
 
 func NewClientConfig(rpcAddr, grpcAddr, chainID string, options ...Option) (ClientConfig, error) {
@@ -302,6 +355,13 @@ func KeyManagerOption(keyManager crypto.KeyManager) Option {
 	}
 }
 
+func SignerOption(signer Signer) Option {
+	return func(cfg *ClientConfig) error {
+		cfg.Signer = signer
+		return nil
+	}
+}
+
 func Bech32AddressPrefixOption(prefix *AddrPrefixCfg) Option {
 	return func(cfg *ClientConfig) error {
 		if prefix != nil {
@@ -358,6 +418,59 @@ func GRPCOptions(gRPCOptions []grpc.DialOption, TLS bool, rpcAddr string) Option
 	}
 }
 
+// LightClientOption verifies the headers of the rpc node with a light client, see TrustedHeader
+func LightClientOption(lightClient LightClientConfig) Option {
+	return func(cfg *ClientConfig) error {
+		if lightClient.TrustHeight <= 0 {
+			return fmt.Errorf("the trust height must be positive")
+		}
+		if len(lightClient.TrustHash) != 32 {
+			return fmt.Errorf("the trust hash must be 32 bytes, got %d", len(lightClient.TrustHash))
+		}
+		if len(lightClient.Witnesses) == 0 {
+			return fmt.Errorf("at least one witness is required to detect forks")
+		}
+		if lightClient.TrustPeriod <= 0 {
+			lightClient.TrustPeriod = defaultTrustPeriod
+		}
+		if lightClient.TrustLevel.Denominator == 0 {
+			lightClient.TrustLevel = tmmath.Fraction{Numerator: 1, Denominator: 3}
+		}
+		cfg.LightClient = &lightClient
+		return nil
+	}
+}
+
+// RPCBatchSizeOption caps the number of calls sent per json-rpc batch request, e.g. by the block
+// lookups of QueryTxs
+func RPCBatchSizeOption(size int) Option {
+	return func(cfg *ClientConfig) error {
+		if size <= 0 {
+			return fmt.Errorf("the rpc batch size must be positive")
+		}
+		cfg.RPCBatchSize = size
+		return nil
+	}
+}
+
+// QueryCacheOption caches the query responses by method, request and height, the responses of the
+// latest height are dropped on each new block except the params
+func QueryCacheOption(queryCache QueryCacheConfig) Option {
+	return func(cfg *ClientConfig) error {
+		if queryCache.Capacity < 0 {
+			return fmt.Errorf("the query cache capacity must not be negative")
+		}
+		if queryCache.Capacity == 0 {
+			queryCache.Capacity = defaultQueryCacheCapacity
+		}
+		if queryCache.ParamsExpiration <= 0 {
+			queryCache.ParamsExpiration = defaultParamsExpiration
+		}
+		cfg.QueryCache = &queryCache
+		return nil
+	}
+}
+
 func FeeGranterOptions(feeGranter string) Option {
 	return func(cfg *ClientConfig) error {
 		granter, err := AccAddressFromBech32(feeGranter)
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/irisnet/core-sdk-go/types/rpc"
 )
 
 type WSClient interface {
@@ -22,6 +24,13 @@ type TmClient interface {
 	WSClient
 	StatusClient
 	NetworkClient
+	MempoolClient
+	BatchClient
+}
+
+// BatchClient sends several json-rpc calls in one request
+type BatchClient interface {
+	NewBatch() *rpc.Batch
 }
 
 type EventKey string
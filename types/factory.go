@@ -213,6 +213,16 @@ func (f *Factory) BuildAndSign(name string, msgs []Msg, json bool) ([]byte, erro
 	return txBytes, nil
 }
 
+// BuildUnsigned returns the JSON of the unsigned tx, it is signed by the holder of the key,
+// e.g. for the watch-only keys
+func (f *Factory) BuildUnsigned(msgs []Msg) ([]byte, error) {
+	tx, err := f.BuildUnsignedTx(msgs)
+	if err != nil {
+		return nil, err
+	}
+	return f.txConfig.TxJSONEncoder()(tx.GetTx())
+}
+
 func (f *Factory) BuildUnsignedTx(msgs []Msg) (TxBuilder, error) {
 	if f.chainID == "" {
 		return nil, fmt.Errorf("chain ID required but not specified")
@@ -272,6 +282,9 @@ func (f *Factory) Sign(name string, txBuilder TxBuilder) error {
 	if err != nil {
 		return err
 	}
+	if pubkey == nil {
+		return fmt.Errorf("%s is a watch-only key without public key, it can't sign", name)
+	}
 
 	// For SIGN_MODE_DIRECT, calling SetSignatures calls setSignerInfos on
 	// Factory under the hood, and SignerInfos is needed to generated the
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"context"
+	"reflect"
+)
+
+// PendingTxStatus describes the lifecycle stage of a transaction observed in the mempool
+type PendingTxStatus string
+
+const (
+	// PendingTxAdded is emitted the first time a transaction is seen in the mempool
+	PendingTxAdded PendingTxStatus = "added"
+	// PendingTxCommitted is emitted when a pending transaction has been included in a block
+	PendingTxCommitted PendingTxStatus = "committed"
+	// PendingTxEvicted is emitted when a pending transaction left the mempool without being committed
+	PendingTxEvicted PendingTxStatus = "evicted"
+)
+
+// PendingTx is a decoded transaction waiting in the mempool
+type PendingTx struct {
+	Hash      string   `json:"hash"`
+	Signers   []string `json:"signers"`
+	Sequences []uint64 `json:"sequences"`
+	Fee       Coins    `json:"fee"`
+	Gas       uint64   `json:"gas"`
+	Memo      string   `json:"memo"`
+	Msgs      []Msg    `json:"msgs"`
+}
+
+// ResultUnconfirmedTxs is the decoded result of a mempool query
+type ResultUnconfirmedTxs struct {
+	Count      int         `json:"count"`
+	Total      int         `json:"total"`
+	TotalBytes int64       `json:"total_bytes"`
+	Txs        []PendingTx `json:"txs"`
+}
+
+// MempoolFilter selects pending transactions by signer/recipient address or by msg type.
+// An empty filter matches every transaction.
+type MempoolFilter struct {
+	// Addresses matches a transaction when any of them signed it or is held by an address field of
+	// one of its msgs, e.g. the recipient of a send
+	Addresses []string `json:"addresses"`
+	// MsgTypes matches a transaction containing a msg whose type url (e.g. "/cosmos.bank.v1beta1.MsgSend")
+	// or legacy type (e.g. "send") is listed
+	MsgTypes []string `json:"msg_types"`
+}
+
+// Match returns whether the pending transaction satisfies the filter
+func (f MempoolFilter) Match(tx PendingTx) bool {
+	return f.matchAddress(tx) && f.matchMsgType(tx)
+}
+
+func (f MempoolFilter) matchAddress(tx PendingTx) bool {
+	if len(f.Addresses) == 0 {
+		return true
+	}
+
+	addresses := make(map[string]bool, len(f.Addresses))
+	for _, addr := range f.Addresses {
+		addresses[addr] = true
+	}
+	for _, signer := range tx.Signers {
+		if addresses[signer] {
+			return true
+		}
+	}
+	for _, msg := range tx.Msgs {
+		for _, signer := range msg.GetSigners() {
+			if addresses[signer.String()] {
+				return true
+			}
+		}
+		if hasAddressField(reflect.ValueOf(msg), addresses) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAddressField walks the fields of a msg, e.g. the recipient of a send or the outputs of a
+// multi-send, a string field matches when it holds one of the addresses
+func hasAddressField(v reflect.Value, addresses map[string]bool) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && hasAddressField(v.Elem(), addresses)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if hasAddressField(v.Field(i), addresses) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasAddressField(v.Index(i), addresses) {
+				return true
+			}
+		}
+	case reflect.String:
+		return addresses[v.String()]
+	}
+	return false
+}
+
+func (f MempoolFilter) matchMsgType(tx PendingTx) bool {
+	if len(f.MsgTypes) == 0 {
+		return true
+	}
+
+	for _, msg := range tx.Msgs {
+		typeURL := MsgTypeURL(msg)
+		for _, typ := range f.MsgTypes {
+			if typ == typeURL || typ == msg.Type() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventDataPendingTx is delivered to the handler of SubscribePendingTx
+type EventDataPendingTx struct {
+	Status PendingTxStatus `json:"status"`
+	Tx     PendingTx       `json:"tx"`
+	// Height and Result are only set when Status is PendingTxCommitted
+	Height int64    `json:"height"`
+	Result TxResult `json:"result"`
+}
+
+type EventPendingTxHandler func(EventDataPendingTx)
+
+// MempoolSubscription is the handle returned by SubscribePendingTx
+type MempoolSubscription struct {
+	ID     string             `json:"id"`
+	Cancel context.CancelFunc `json:"-"`
+}
+
+// Unsubscribe stops watching the mempool
+func (s MempoolSubscription) Unsubscribe() {
+	if s.Cancel != nil {
+		s.Cancel()
+	}
+}
@@ -0,0 +1,54 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/core-sdk-go/bank"
+	sdk "github.com/irisnet/core-sdk-go/types"
+)
+
+func TestMempoolFilter_Match(t *testing.T) {
+	from := sdk.AccAddress([]byte("from________________"))
+	to := sdk.AccAddress([]byte("to__________________"))
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	msg := bank.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1)))
+	tx := sdk.PendingTx{
+		Signers: []string{from.String()},
+		Msgs:    []sdk.Msg{msg},
+	}
+	multiSend := sdk.PendingTx{
+		Msgs: []sdk.Msg{bank.NewMsgMultiSend(
+			[]bank.Input{bank.NewInput(from, sdk.NewCoins(sdk.NewInt64Coin("uiris", 2)))},
+			[]bank.Output{
+				bank.NewOutput(to, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1))),
+				bank.NewOutput(other, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1))),
+			},
+		)},
+	}
+
+	cases := []struct {
+		name   string
+		filter sdk.MempoolFilter
+		tx     sdk.PendingTx
+		match  bool
+	}{
+		{"empty filter", sdk.MempoolFilter{}, tx, true},
+		{"signer", sdk.MempoolFilter{Addresses: []string{from.String()}}, tx, true},
+		{"recipient", sdk.MempoolFilter{Addresses: []string{to.String()}}, tx, true},
+		{"unrelated address", sdk.MempoolFilter{Addresses: []string{other.String()}}, tx, false},
+		{"part of an address", sdk.MempoolFilter{Addresses: []string{to.String()[:20]}}, tx, false},
+		{"multi-send signer", sdk.MempoolFilter{Addresses: []string{from.String()}}, multiSend, true},
+		{"multi-send output", sdk.MempoolFilter{Addresses: []string{other.String()}}, multiSend, true},
+		{"type url", sdk.MempoolFilter{MsgTypes: []string{sdk.MsgTypeURL(msg)}}, tx, true},
+		{"legacy type", sdk.MempoolFilter{MsgTypes: []string{msg.Type()}}, tx, true},
+		{"other type", sdk.MempoolFilter{MsgTypes: []string{"/cosmos.staking.v1beta1.MsgDelegate"}}, tx, false},
+		{"address and other type", sdk.MempoolFilter{Addresses: []string{from.String()}, MsgTypes: []string{"delegate"}}, tx, false},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.match, c.filter.Match(c.tx), c.name)
+	}
+}
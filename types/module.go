@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/tendermint/tendermint/crypto"
 
 	codectypes "github.com/irisnet/core-sdk-go/common/codec/types"
@@ -22,13 +24,132 @@ type Module interface {
 	RegisterInterfaceTypes(registry codectypes.InterfaceRegistry)
 }
 
-type KeyManager interface {
+// HeightModule is a Module whose queries can be pinned to a height, the registered modules of a
+// client are rebuilt on the client of WithHeight
+type HeightModule interface {
+	Module
+	// WithBaseClient returns a copy of the module querying with the base client
+	WithBaseClient(base BaseClient) Module
+}
+
+// Signer signs with the keys it holds, it is all a client needs to build and sign txs
+type Signer interface {
 	Sign(name, password string, data []byte) ([]byte, crypto.PubKey, error)
+	Find(name, password string) (crypto.PubKey, AccAddress, error)
+}
+
+// KeyLister lists the keys held by a Signer
+type KeyLister interface {
+	List() ([]string, error)
+	ListMetadata() ([]KeyMetadata, error)
+}
+
+// KeyManager manages the keys of a Signer. The other features of a key manager are optional
+// interfaces, e.g. KeyLister or HDWallets, asserted by the keys client.
+type KeyManager interface {
+	Signer
 	Insert(name, password string) (string, string, error)
 	Recover(name, password, mnemonic, hdPath string) (string, error)
 	Import(name, password string, privKeyArmor string) (address string, err error)
 	Export(name, password string) (privKeyArmor string, err error)
 	Delete(name, password string) error
-	Find(name, password string) (crypto.PubKey, AccAddress, error)
 	Add(name, password string) (address string, mnemonic string, err Error)
 }
+
+// KeyEditor renames the keys and changes their password
+type KeyEditor interface {
+	Rename(oldName, newName, password string) error
+	ChangePassword(name, oldPassword, newPassword string) error
+}
+
+// KeySessions keep decrypted keys in memory
+type KeySessions interface {
+	// Unlock keeps the decrypted key in memory for ttl, Sign and Find skip the decryption meanwhile
+	Unlock(name, password string, ttl time.Duration) error
+	// Lock forgets the decrypted key
+	Lock(name string)
+}
+
+// HDWallets store mnemonics and derive their accounts
+type HDWallets interface {
+	// CreateHDWallet stores a mnemonic as a wallet, its accounts are signed with as "name/account/index"
+	CreateHDWallet(name, password, mnemonic string, coinType uint32) (address string, mnemonicOut string, err error)
+	// DeriveHDAccounts derives count accounts from fromIndex without recording them
+	DeriveHDAccounts(name, password string, account, fromIndex, count uint32) ([]HDAccount, error)
+	AddHDAccounts(name, password string, accounts ...HDAccount) error
+	// HDAccounts returns the accounts recorded in the wallet
+	HDAccounts(name, password string) ([]HDAccount, error)
+}
+
+// KeyFormats import and export keys in the formats of other wallets
+type KeyFormats interface {
+	// ImportKeystore imports the eth_secp256k1 key of a Web3 Secret Storage V3 keystore
+	ImportKeystore(name, password, keystore string) (address string, err error)
+	// ExportKeystore exports an eth_secp256k1 key as a V3 keystore encrypted with kdf, scrypt or pbkdf2
+	ExportKeystore(name, password, kdf string) (keystore string, err error)
+	// ImportPKCS8 imports the SM2 key of a PKCS#8 PEM, encrypted keys are decrypted with the password
+	ImportPKCS8(name, password, pem string) (address string, err error)
+	// ExportPKCS8 exports an SM2 key as a PKCS#8 PEM encrypted with SM4
+	ExportPKCS8(name, password string) (pem string, err error)
+}
+
+// MnemonicKeys generate and recover keys with the options of the other wallets
+type MnemonicKeys interface {
+	// InsertWithOptions generates a mnemonic of the requested size and language
+	InsertWithOptions(name, password string, opts MnemonicOptions) (address string, mnemonic string, err error)
+	// RecoverWithOptions recovers a key protected by a BIP39 passphrase or written in another language
+	RecoverWithOptions(name, password, mnemonic string, opts MnemonicOptions) (address string, err error)
+}
+
+// WatchOnlyKeys store keys without their private key
+type WatchOnlyKeys interface {
+	// ImportPubKey stores a watch-only key, its txs can be built but are signed elsewhere
+	ImportPubKey(name, password string, pubKey crypto.PubKey) (address string, err error)
+	// ImportAddress stores a watch-only key known only by its bech32 address
+	ImportAddress(name, password, address string) error
+}
+
+// KeyShares split keys into Shamir shares
+type KeyShares interface {
+	// ExportShares splits a key into count armored shares, threshold of them restore it
+	ExportShares(name, password string, threshold, count int) (shares []string, err error)
+	// ImportShares restores a key from the shares of ExportShares
+	ImportShares(name, password string, shares []string) (address string, err error)
+}
+
+// MnemonicOptions customize the generation and the recovery of a key from a mnemonic
+type MnemonicOptions struct {
+	// BIP39Passphrase is the optional "25th word" set in Keplr or Ledger
+	BIP39Passphrase string
+	// WordCount of a generated mnemonic, 12, 15, 18, 21 or 24, defaults to 24
+	WordCount int
+	// Language of the wordlist, see hd.RegisterWordlist. A generated mnemonic defaults to English,
+	// the language of a recovered mnemonic is detected when empty.
+	Language string
+	// HDPath defaults to hd.FullPath
+	HDPath string
+}
+
+// HDAccount is an account derived from an HD wallet
+type HDAccount struct {
+	// Name is used to sign with the account, e.g. "wallet/0/1"
+	Name    string `json:"name"`
+	Account uint32 `json:"account"`
+	Index   uint32 `json:"index"`
+	HDPath  string `json:"hd_path"`
+	Address string `json:"address"`
+}
+
+// KeyMetadata is the public information of a stored key
+type KeyMetadata struct {
+	Name string `json:"name"`
+	// Address and PubKey are empty when the store keeps the public key encrypted
+	Address string `json:"address"`
+	// EthAddress is the 0x hex address of eth_secp256k1 keys
+	EthAddress string        `json:"eth_address,omitempty"`
+	PubKey     crypto.PubKey `json:"pubkey"`
+	Algo       string        `json:"algo"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// WatchOnly keys have no private key, PubKey is empty when only the address is known
+	WatchOnly bool `json:"watch_only,omitempty"`
+}
@@ -0,0 +1,140 @@
+package query
+
+import "fmt"
+
+// MaxPageLimit is the largest page accepted by the nodes
+const MaxPageLimit = 100
+
+// IterateOptions controls the behavior of a PageIterator
+type IterateOptions struct {
+	// PageSize is the number of items requested per page, at most 100, defaults to 100
+	PageSize uint64
+	// Limit stops the iteration after the given number of items, 0 means no limit
+	Limit int
+	// Prefetch fetches the next page while the current one is consumed
+	Prefetch bool
+}
+
+// PageFetcher fetches the page of the request, it returns the items of the page and the
+// PageResponse holding the key of the next page
+type PageFetcher func(pageReq *PageRequest) ([]interface{}, *PageResponse, error)
+
+type fetchedPage struct {
+	items []interface{}
+	res   *PageResponse
+	err   error
+}
+
+// PageIterator walks through the items of a paginated query, following PageResponse.NextKey
+// until it is empty. Unlike offsets the next key is not shifted by the items inserted meanwhile.
+//
+//	it := client.Staking.IterateValidatorDelegations(validator, query.IterateOptions{Prefetch: true})
+//	for it.Next() {
+//		delegation := it.Value().(staking.QueryDelegationResp)
+//	}
+//	err = it.Err()
+type PageIterator struct {
+	fetch PageFetcher
+	opts  IterateOptions
+
+	next    chan fetchedPage
+	nextKey []byte
+	done    bool
+	emitted int
+
+	buf []interface{}
+	cur interface{}
+	err error
+}
+
+// NewPageIterator returns an iterator fetching the pages with the fetcher on demand
+func NewPageIterator(fetch PageFetcher, opts IterateOptions) *PageIterator {
+	if opts.PageSize == 0 || opts.PageSize > MaxPageLimit {
+		opts.PageSize = MaxPageLimit
+	}
+	return &PageIterator{fetch: fetch, opts: opts}
+}
+
+// Next advances the iterator, it returns false when the items are exhausted or an error occurred
+func (it *PageIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.opts.Limit > 0 && it.emitted >= it.opts.Limit {
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if it.done {
+			return false
+		}
+		if it.err = it.nextPage(); it.err != nil {
+			return false
+		}
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	it.emitted++
+	return true
+}
+
+// Value returns the current item
+func (it *PageIterator) Value() interface{} {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator
+func (it *PageIterator) All() ([]interface{}, error) {
+	var items []interface{}
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+func (it *PageIterator) nextPage() error {
+	var page fetchedPage
+	if it.next != nil {
+		page = <-it.next
+		it.next = nil
+	} else {
+		page = it.fetchPage(it.nextKey)
+	}
+	if page.err != nil {
+		return page.err
+	}
+
+	if page.res == nil || len(page.res.NextKey) == 0 {
+		it.done = true
+	} else {
+		if string(page.res.NextKey) == string(it.nextKey) {
+			return fmt.Errorf("the next key %X does not advance", page.res.NextKey)
+		}
+		it.nextKey = page.res.NextKey
+	}
+	it.buf = page.items
+
+	if !it.done && it.opts.Prefetch && !it.limitReached() {
+		// buffered, the fetch never blocks when the iterator is abandoned
+		it.next = make(chan fetchedPage, 1)
+		go func(key []byte, next chan<- fetchedPage) {
+			next <- it.fetchPage(key)
+		}(it.nextKey, it.next)
+	}
+	return nil
+}
+
+func (it *PageIterator) fetchPage(key []byte) fetchedPage {
+	items, res, err := it.fetch(&PageRequest{Key: key, Limit: it.opts.PageSize})
+	return fetchedPage{items: items, res: res, err: err}
+}
+
+// limitReached returns true when the buffered items reach the limit
+func (it *PageIterator) limitReached() bool {
+	return it.opts.Limit > 0 && it.emitted+len(it.buf) >= it.opts.Limit
+}
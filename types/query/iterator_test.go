@@ -0,0 +1,110 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// keyedStore pages its items by key, the next key is the key of the first item of the next page
+type keyedStore struct {
+	mu       sync.Mutex
+	items    []int
+	requests []*PageRequest
+	fail     bool
+	stuck    bool
+}
+
+func (s *keyedStore) fetch(pageReq *PageRequest) ([]interface{}, *PageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, pageReq)
+	if s.fail {
+		return nil, nil, errors.New("node unavailable")
+	}
+
+	start := 0
+	if len(pageReq.Key) > 0 {
+		fmt.Sscanf(string(pageReq.Key), "%d", &start)
+	}
+	end := start + int(pageReq.Limit)
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+
+	var page []interface{}
+	for _, item := range s.items[start:end] {
+		page = append(page, item)
+	}
+	res := &PageResponse{}
+	if end < len(s.items) {
+		res.NextKey = []byte(fmt.Sprintf("%d", end))
+	}
+	if s.stuck {
+		res.NextKey = pageReq.Key
+		if len(res.NextKey) == 0 {
+			res.NextKey = []byte("0")
+		}
+	}
+	return page, res, nil
+}
+
+func newKeyedStore(n int) *keyedStore {
+	s := &keyedStore{}
+	for i := 0; i < n; i++ {
+		s.items = append(s.items, i)
+	}
+	return s
+}
+
+func TestPageIterator(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		s := newKeyedStore(25)
+		items, err := NewPageIterator(s.fetch, IterateOptions{PageSize: 10, Prefetch: prefetch}).All()
+		require.NoError(t, err)
+		require.Len(t, items, 25)
+		for i, item := range items {
+			require.Equal(t, i, item)
+		}
+		require.Len(t, s.requests, 3)
+		require.Empty(t, s.requests[0].Key)
+		require.Equal(t, []byte("10"), s.requests[1].Key)
+		require.Equal(t, []byte("20"), s.requests[2].Key)
+	}
+
+	// the page size is capped
+	s := newKeyedStore(0)
+	items, err := NewPageIterator(s.fetch, IterateOptions{PageSize: 1000}).All()
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.Equal(t, uint64(MaxPageLimit), s.requests[0].Limit)
+}
+
+func TestPageIteratorLimit(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		s := newKeyedStore(25)
+		items, err := NewPageIterator(s.fetch, IterateOptions{PageSize: 10, Limit: 15, Prefetch: prefetch}).All()
+		require.NoError(t, err)
+		require.Len(t, items, 15)
+		// the page past the limit is not fetched
+		require.Len(t, s.requests, 2)
+	}
+}
+
+func TestPageIteratorErrors(t *testing.T) {
+	s := newKeyedStore(5)
+	s.fail = true
+	it := NewPageIterator(s.fetch, IterateOptions{})
+	require.False(t, it.Next())
+	require.EqualError(t, it.Err(), "node unavailable")
+
+	s = newKeyedStore(5)
+	s.stuck = true
+	it = NewPageIterator(s.fetch, IterateOptions{PageSize: 2})
+	_, err := it.All()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not advance")
+}
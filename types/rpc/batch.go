@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// DefaultBatchSize is the number of calls sent per request of a Batch when the client sets none
+const DefaultBatchSize = 20
+
+// Batch collects json-rpc calls and sends them together, the calls are split into requests of at
+// most the batch size of the client.
+//
+//	results, err := client.NewBatch().Block(h1).BlockResults(h1).Block(h2).Send(ctx)
+//	block := results[0].(*ctypes.ResultBlock)
+type Batch struct {
+	client JSONRpcClient
+	calls  []batchCall
+}
+
+type batchCall struct {
+	method string
+	params map[string]interface{}
+	result interface{}
+}
+
+// NewBatch returns an empty batch of the client
+func (c JSONRpcClient) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Block adds a call returning the *ctypes.ResultBlock at the height
+func (b *Batch) Block(height int64) *Batch {
+	return b.add("block", map[string]interface{}{"height": strconv.FormatInt(height, 10)}, new(ctypes.ResultBlock))
+}
+
+// BlockResults adds a call returning the *ctypes.ResultBlockResults at the height
+func (b *Batch) BlockResults(height int64) *Batch {
+	return b.add("block_results", map[string]interface{}{"height": strconv.FormatInt(height, 10)}, new(ctypes.ResultBlockResults))
+}
+
+// Commit adds a call returning the *ctypes.ResultCommit at the height
+func (b *Batch) Commit(height int64) *Batch {
+	return b.add("commit", map[string]interface{}{"height": strconv.FormatInt(height, 10)}, new(ctypes.ResultCommit))
+}
+
+// Tx adds a call returning the *ctypes.ResultTx of the hash
+func (b *Batch) Tx(hash []byte, prove bool) *Batch {
+	return b.add("tx", map[string]interface{}{"hash": hash, "prove": prove}, new(ctypes.ResultTx))
+}
+
+// ABCIQueryWithOptions adds a call returning the *ctypes.ResultABCIQuery of the path
+func (b *Batch) ABCIQueryWithOptions(path string, data tmbytes.HexBytes, opts client.ABCIQueryOptions) *Batch {
+	params := map[string]interface{}{
+		"path":   path,
+		"data":   data,
+		"height": strconv.FormatInt(opts.Height, 10),
+		"prove":  opts.Prove,
+	}
+	return b.add("abci_query", params, new(ctypes.ResultABCIQuery))
+}
+
+// Len returns the number of calls of the batch
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Send sends the calls and returns their results in the order they were added, it fails when any
+// call fails
+func (b *Batch) Send(ctx context.Context) ([]interface{}, error) {
+	size := b.client.batchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	results := make([]interface{}, len(b.calls))
+	for start := 0; start < len(b.calls); start += size {
+		end := start + size
+		if end > len(b.calls) {
+			end = len(b.calls)
+		}
+		if err := b.send(ctx, start, end); err != nil {
+			return nil, err
+		}
+	}
+	for i, call := range b.calls {
+		results[i] = call.result
+	}
+	return results, nil
+}
+
+func (b *Batch) add(method string, params map[string]interface{}, result interface{}) *Batch {
+	b.calls = append(b.calls, batchCall{method: method, params: params, result: result})
+	return b
+}
+
+// send sends the calls [start, end) in one request, the ids are the indexes of the calls
+func (b *Batch) send(ctx context.Context, start, end int) error {
+	requests := make([]map[string]interface{}, 0, end-start)
+	for i := start; i < end; i++ {
+		requests = append(requests, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      i,
+			"method":  b.calls[i].method,
+			"params":  b.calls[i].params,
+		})
+	}
+	requestBytes, err := json.Marshal(requests)
+	if err != nil {
+		return fmt.Errorf("request failed: %s", err.Error())
+	}
+
+	httpResponseBytes, err := b.client.post(ctx, requestBytes)
+	if err != nil {
+		return err
+	}
+	var rpcResponses []types.RPCResponse
+	if err := json.Unmarshal(httpResponseBytes, &rpcResponses); err != nil {
+		// the whole batch is rejected with a single response
+		rpcResponse := &types.RPCResponse{}
+		if json.Unmarshal(httpResponseBytes, rpcResponse) == nil && rpcResponse.Error != nil {
+			return fmt.Errorf("batch request failed, %w", newRPCError(rpcResponse.Error))
+		}
+		return fmt.Errorf("error unmarshalling: %s", err.Error())
+	}
+
+	received := make(map[int]bool, len(rpcResponses))
+	for _, rpcResponse := range rpcResponses {
+		id, ok := rpcResponse.ID.(types.JSONRPCIntID)
+		if !ok || int(id) < start || int(id) >= end {
+			return fmt.Errorf("unexpected response id %v", rpcResponse.ID)
+		}
+		call := b.calls[id]
+		if rpcResponse.Error != nil {
+			return fmt.Errorf("request %d (%s) failed, %w", id, call.method, newRPCError(rpcResponse.Error))
+		}
+		if err := tmjson.Unmarshal(rpcResponse.Result, call.result); err != nil {
+			return fmt.Errorf("error unmarshalling result of request %d (%s): %s", id, call.method, err.Error())
+		}
+		received[int(id)] = true
+	}
+	if len(received) != end-start {
+		return fmt.Errorf("got %d responses for %d requests", len(received), end-start)
+	}
+	return nil
+}
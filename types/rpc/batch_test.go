@@ -0,0 +1,104 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// batchNode answers the block and block_results calls of the batches it receives
+type batchNode struct {
+	batches []int
+}
+
+func (n *batchNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var requests []types.RPCRequest
+	if err := json.Unmarshal(body, &requests); err != nil {
+		_ = json.NewEncoder(w).Encode(types.RPCParseError(err))
+		return
+	}
+	n.batches = append(n.batches, len(requests))
+
+	// answered in reverse order, the responses are matched by id
+	var responses []types.RPCResponse
+	for i := len(requests) - 1; i >= 0; i-- {
+		req := requests[i]
+		var params map[string]string
+		_ = json.Unmarshal(req.Params, &params)
+		height, _ := strconv.ParseInt(params["height"], 10, 64)
+		if height <= 0 {
+			responses = append(responses, types.RPCInvalidParamsError(req.ID, errors.New("height must be greater than 0")))
+			continue
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "block":
+			result = &ctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{
+				Height: height,
+				Time:   time.Unix(height, 0).UTC(),
+			}}}
+		case "block_results":
+			result = &ctypes.ResultBlockResults{Height: height}
+		}
+		bz, _ := tmjson.Marshal(result)
+		responses = append(responses, types.RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: bz})
+	}
+	_ = json.NewEncoder(w).Encode(responses)
+}
+
+func newBatchClient(t *testing.T, batchSize int) (JSONRpcClient, *batchNode) {
+	node := &batchNode{}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	client := JSONRpcClient{remote: server.URL, client: server.Client()}
+	return client.WithBatchSize(batchSize), node
+}
+
+func TestBatch(t *testing.T) {
+	client, node := newBatchClient(t, 2)
+
+	batch := client.NewBatch().Block(1).BlockResults(1).Block(2)
+	require.Equal(t, 3, batch.Len())
+	results, err := batch.Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 1}, node.batches)
+
+	require.Len(t, results, 3)
+	require.Equal(t, int64(1), results[0].(*ctypes.ResultBlock).Block.Height)
+	require.Equal(t, time.Unix(1, 0).UTC(), results[0].(*ctypes.ResultBlock).Block.Time)
+	require.Equal(t, int64(1), results[1].(*ctypes.ResultBlockResults).Height)
+	require.Equal(t, int64(2), results[2].(*ctypes.ResultBlock).Block.Height)
+
+	// an empty batch sends nothing
+	results, err = client.NewBatch().Send(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Len(t, node.batches, 2)
+}
+
+func TestBatchErrors(t *testing.T) {
+	client, _ := newBatchClient(t, 0)
+
+	_, err := client.NewBatch().Block(1).Block(-1).Send(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "request 1 (block) failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.NewBatch().Block(1).Send(ctx)
+	require.Error(t, err)
+}
@@ -25,12 +25,38 @@ import (
 
 var errNotRunning = errors.New("client is not running. Use .Start() method to start")
 
+// codeInternalError is the json-rpc code of the errors returned by the rpc methods of tendermint
+const codeInternalError = -32603
+
+// RPCError is the error a json-rpc call is answered with
+type RPCError struct {
+	Code    int
+	Message string
+	Data    string
+}
+
+func newRPCError(err *types.RPCError) *RPCError {
+	return &RPCError{Code: err.Code, Message: err.Message, Data: err.Data}
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("code: %d, message: %s, data: %s", e.Code, e.Message, e.Data)
+}
+
+// IsTxNotFound returns true when the error answers a tx call of the hash unknown to the node
+func IsTxNotFound(err error, hash []byte) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == codeInternalError &&
+		rpcErr.Data == fmt.Sprintf("tx (%X) not found", hash)
+}
+
 var _ service.Service = (*JSONRpcClient)(nil)
 
 type JSONRpcClient struct {
-	remote string
-	client *http.Client
-	header http.Header
+	remote    string
+	client    *http.Client
+	header    http.Header
+	batchSize int
 	*WSEvents
 }
 
@@ -71,6 +97,18 @@ func (c JSONRpcClient) String() string {
 	return ""
 }
 
+// WithBatchSize returns a copy of the client sending at most size calls per request of a Batch,
+// DefaultBatchSize when size is not positive
+func (c JSONRpcClient) WithBatchSize(size int) JSONRpcClient {
+	c.batchSize = size
+	return c
+}
+
+// Remote returns the rpc address
+func (c JSONRpcClient) Remote() string {
+	return c.remote
+}
+
 func (c JSONRpcClient) SetLogger(logger log.Logger) {
 	c.WSEvents.SetLogger(logger)
 }
@@ -354,7 +392,26 @@ func (c *JSONRpcClient) Call(ctx context.Context, method string, params map[stri
 		return nil, fmt.Errorf("request failed: %s", err.Error())
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.remote, bytes.NewReader(requestBytes))
+	httpResponseBytes, err := c.post(ctx, requestBytes)
+	if err != nil {
+		return nil, err
+	}
+	rpcResponse := &types.RPCResponse{}
+	if err = json.Unmarshal(httpResponseBytes, rpcResponse); err != nil {
+		return nil, fmt.Errorf("error unmarshalling: %s", err.Error())
+	}
+	if rpcResponse.Error != nil {
+		return nil, fmt.Errorf("request failed, %w", newRPCError(rpcResponse.Error))
+	}
+	if err = tmjson.Unmarshal(rpcResponse.Result, result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling result: %s", err.Error())
+	}
+	return result, nil
+}
+
+// post sends the request body to the remote and returns the response body
+func (c *JSONRpcClient) post(ctx context.Context, requestBytes []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.remote, bytes.NewReader(requestBytes))
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %s", err.Error())
 	}
@@ -375,17 +432,7 @@ func (c *JSONRpcClient) Call(ctx context.Context, method string, params map[stri
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %s", err.Error())
 	}
-	rpcResponse := &types.RPCResponse{}
-	if err = json.Unmarshal(httpResponseBytes, rpcResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshalling: %s", err.Error())
-	}
-	if rpcResponse.Error != nil {
-		return nil, fmt.Errorf("request failed, code: %d, message: %s, data: %s", rpcResponse.Error.Code, rpcResponse.Error.Message, rpcResponse.Error.Data)
-	}
-	if err = tmjson.Unmarshal(rpcResponse.Result, result); err != nil {
-		return nil, fmt.Errorf("error unmarshalling result: %s", err.Error())
-	}
-	return result, nil
+	return httpResponseBytes, nil
 }
 
 func (c JSONRpcClient) broadcastTX(ctx context.Context, route string, tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
@@ -251,6 +251,7 @@ type ResultTx struct {
 type ResultQueryTx struct {
 	Hash      string      `json:"hash"`
 	Height    int64       `json:"height"`
+	Index     uint32      `json:"index"`
 	Tx        UnwrappedTx `json:"tx"`
 	Result    TxResult    `json:"result"`
 	Timestamp string      `json:"timestamp"`
@@ -261,3 +262,44 @@ type ResultSearchTxs struct {
 	Total int             `json:"total"` // Count of all txs
 	Txs   []ResultQueryTx `json:"txs"`   // List of txs in current page
 }
+
+// TxSearchOrder defines the order in which searched txs are returned
+type TxSearchOrder string
+
+const (
+	TxSearchOrderAsc  TxSearchOrder = "asc"
+	TxSearchOrderDesc TxSearchOrder = "desc"
+)
+
+// TxSearchOptions controls the behavior of a TxIterator
+type TxSearchOptions struct {
+	// Order of the results by height, defaults to asc
+	Order TxSearchOrder `json:"order"`
+	// MinHeight and MaxHeight restrict the search to an inclusive height range, 0 means unbounded
+	MinHeight int64 `json:"min_height"`
+	MaxHeight int64 `json:"max_height"`
+	// PageSize is the number of txs requested per page, at most 100
+	PageSize int `json:"page_size"`
+	// Limit stops the iteration after the given number of txs, 0 means no limit
+	Limit int `json:"limit"`
+	// Concurrency is the number of workers used to fetch block times
+	Concurrency int `json:"concurrency"`
+}
+
+// TxIterator walks through the results of a tx search, fetching pages on demand.
+//
+//	it, err := client.IterateTxs(builder, TxSearchOptions{Order: TxSearchOrderDesc, Limit: 50})
+//	for it.Next() {
+//		tx := it.Value()
+//	}
+//	err = it.Err()
+type TxIterator interface {
+	// Next advances the iterator, it returns false when the results are exhausted or an error occurred
+	Next() bool
+	// Value returns the current tx
+	Value() ResultQueryTx
+	// Total returns the number of txs matching the query, as reported by the node
+	Total() int
+	// Err returns the error that stopped the iteration, if any
+	Err() error
+}
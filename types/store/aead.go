@@ -0,0 +1,147 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	envelopeV1Prefix = "v1:"
+
+	scryptSaltLen = 16
+	scryptKeyLen  = 32
+
+	defaultScryptLogN = 15
+	defaultScryptR    = 8
+	defaultScryptP    = 1
+
+	// the parameters are read from the envelope before it is authenticated, so they are bounded
+	// to keep a tampered entry from making scrypt allocate gigabytes (128·r·N bytes)
+	maxScryptLogN   = 20
+	maxScryptRP     = 32
+	maxScryptMemory = 256 << 20
+)
+
+var _ Crypto = AESGCM{}
+
+// AESGCM encrypts with AES-256-GCM under a key stretched from the password by scrypt with a random salt.
+// The output is a versioned envelope `v1:` + base64(logN | r | p | salt | nonce | ciphertext+tag),
+// the scrypt parameters travel with the data so they can be raised later without breaking old keys.
+// A wrong password fails to decrypt instead of returning garbage.
+type AESGCM struct {
+	// ScryptLogN is log2 of the scrypt cost parameter N, defaults to 15, at most 20
+	ScryptLogN uint8
+	// ScryptR is the scrypt block size, defaults to 8, 128·r·N is at most 256MiB
+	ScryptR uint8
+	// ScryptP is the scrypt parallelization, defaults to 1, r·p is at most 32
+	ScryptP uint8
+}
+
+func (a AESGCM) Encrypt(text string, password string) (string, error) {
+	logN, r, p := a.params()
+
+	header := make([]byte, 3+scryptSaltLen)
+	header[0], header[1], header[2] = logN, r, p
+	salt := header[3:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(password, salt, logN, r, p)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	// the header is authenticated so the parameters cannot be tampered with
+	sealed := gcm.Seal(nil, nonce, []byte(text), header)
+
+	envelope := make([]byte, 0, len(header)+len(nonce)+len(sealed))
+	envelope = append(envelope, header...)
+	envelope = append(envelope, nonce...)
+	envelope = append(envelope, sealed...)
+	return envelopeV1Prefix + base64.URLEncoding.EncodeToString(envelope), nil
+}
+
+func (a AESGCM) Decrypt(cryptoText string, password string) (string, error) {
+	if !IsEnvelope(cryptoText) {
+		return "", fmt.Errorf("unsupported ciphertext format")
+	}
+
+	envelope, err := base64.URLEncoding.DecodeString(strings.TrimPrefix(cryptoText, envelopeV1Prefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext")
+	}
+
+	headerLen := 3 + scryptSaltLen
+	if len(envelope) < headerLen {
+		return "", fmt.Errorf("invalid ciphertext")
+	}
+	header := envelope[:headerLen]
+
+	gcm, err := newGCM(password, header[3:], header[0], header[1], header[2])
+	if err != nil {
+		return "", err
+	}
+
+	body := envelope[headerLen:]
+	if len(body) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid ciphertext")
+	}
+	nonce, sealed := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, header)
+	if err != nil {
+		return "", fmt.Errorf("wrong password")
+	}
+	return string(plaintext), nil
+}
+
+func (a AESGCM) params() (logN, r, p uint8) {
+	logN, r, p = a.ScryptLogN, a.ScryptR, a.ScryptP
+	if logN == 0 {
+		logN = defaultScryptLogN
+	}
+	if r == 0 {
+		r = defaultScryptR
+	}
+	if p == 0 {
+		p = defaultScryptP
+	}
+	return logN, r, p
+}
+
+func newGCM(password string, salt []byte, logN, r, p uint8) (cipher.AEAD, error) {
+	if logN == 0 || logN > maxScryptLogN || r == 0 || p == 0 ||
+		int(r)*int(p) > maxScryptRP || 128*int64(r)<<logN > maxScryptMemory {
+		return nil, fmt.Errorf("invalid scrypt parameters")
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, 1<<logN, int(r), int(p), scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEnvelope returns whether the data was encrypted by AESGCM, legacy AES ciphertexts are
+// plain base64 and never contain the version prefix
+func IsEnvelope(data string) bool {
+	return strings.HasPrefix(data, envelopeV1Prefix)
+}
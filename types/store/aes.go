@@ -9,6 +9,8 @@ import (
 	"io"
 )
 
+// AES is the legacy unauthenticated AES-CFB crypto, only kept to read keys written by older versions.
+// Prefer AESGCM.
 type AES struct{}
 
 func (AES) Encrypt(text string, key string) (string, error) {
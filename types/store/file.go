@@ -1,6 +1,8 @@
 package store
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/irisnet/core-sdk-go/common/crypto/codec"
@@ -8,6 +10,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,14 +19,25 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/mtibben/percent"
 	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto"
 )
 
 const (
 	keyringFileDirName = "keyring-file"
+	tmpFileSuffix      = ".tmp"
+
+	fileHeaderCreated = "created"
+	fileHeaderName    = "name"
+	fileHeaderAlgo    = "algo"
+	fileHeaderPubKey  = "pubkey"
+	fileHeaderType    = "type"
+	fileHeaderAddress = "address"
 )
 
 var (
-	_ KeyDAO = FileDAO{}
+	_ KeyDAO         = FileDAO{}
+	_ KeyReplacer    = FileDAO{}
+	_ MetadataReader = FileDAO{}
 
 	filenameEscape = func(s string) string {
 		return percent.Encode(s, "/")
@@ -43,9 +57,22 @@ func NewFileDAO(dir string) KeyDAO {
 
 // Write will use user password to encrypt data and save to file, the file name is user name
 func (f FileDAO) Write(name, password string, info KeyInfo) error {
-	pubkey, err := PubKeyFromBytes(info.PubKey)
-	if err != nil {
-		return err
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = time.Now().UTC()
+	}
+	return f.write(name, password, info)
+}
+
+// write encrypts the info into a temporary file then moves it in place, so an existing key is
+// never left half written. The public fields are copied in the protected JWE header to be
+// listed without the password.
+func (f FileDAO) write(name, password string, info KeyInfo) error {
+	var pubkey crypto.PubKey
+	if len(info.PubKey) > 0 || !info.IsWatchOnly() {
+		var err error
+		if pubkey, err = PubKeyFromBytes(info.PubKey); err != nil {
+			return err
+		}
 	}
 
 	lInfo := localInfo{
@@ -65,9 +92,21 @@ func (f FileDAO) Write(name, password string, info KeyInfo) error {
 		return err
 	}
 
+	headers := map[string]interface{}{
+		fileHeaderCreated: info.CreatedAt.Round(0).String(),
+		fileHeaderName:    info.Name,
+		fileHeaderAlgo:    info.Algo,
+		fileHeaderPubKey:  base64.StdEncoding.EncodeToString(info.PubKey),
+	}
+	// the amino localInfo can't carry the fields of the watch-only keys
+	if info.IsWatchOnly() {
+		headers[fileHeaderType] = float64(info.Type)
+		headers[fileHeaderAddress] = info.Address.String()
+	}
+
 	token, err := jose.Encrypt(
 		string(bytes), jose.PBES2_HS256_A128KW, jose.A256GCM, password,
-		jose.Headers(map[string]interface{}{"created": time.Now().String()}),
+		jose.Headers(headers),
 	)
 	if err != nil {
 		return err
@@ -78,7 +117,11 @@ func (f FileDAO) Write(name, password string, info KeyInfo) error {
 		return err
 	}
 
-	return ioutil.WriteFile(filename, []byte(token), 0600)
+	tmp := filename + tmpFileSuffix
+	if err := ioutil.WriteFile(tmp, []byte(token), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
 }
 
 // Read will read encrypted data from file and decrypt with user password
@@ -97,7 +140,7 @@ func (f FileDAO) Read(name, password string) (KeyInfo, error) {
 		return KeyInfo{}, errors.Wrap(err, "not found")
 	}
 
-	payload, _, err := jose.Decode(string(bytes), password)
+	payload, headers, err := jose.Decode(string(bytes), password)
 	if err != nil {
 		return KeyInfo{}, err
 	}
@@ -118,12 +161,19 @@ func (f FileDAO) Read(name, password string) (KeyInfo, error) {
 		return KeyInfo{}, fmt.Errorf("only support type KeyInfo")
 	}
 
-	return KeyInfo{
+	res := KeyInfo{
 		Name:         i.Name,
-		PubKey:       codec.MarshalPubkey(i.PubKey),
 		PrivKeyArmor: i.PrivKeyArmor,
 		Algo:         string(i.Algo),
-	}, nil
+		CreatedAt:    parseCreated(headers[fileHeaderCreated]),
+	}
+	if i.PubKey != nil {
+		res.PubKey = codec.MarshalPubkey(i.PubKey)
+	}
+	if err := parseWatchOnly(headers, &res); err != nil {
+		return KeyInfo{}, fmt.Errorf("key %s: %s", name, err.Error())
+	}
+	return res, nil
 }
 
 // Delete will delete user data and use user password to verify permissions
@@ -156,6 +206,203 @@ func (f FileDAO) Has(name string) bool {
 	return false
 }
 
+// List returns the names of all the keys in alphabetical order
+func (f FileDAO) List() ([]string, error) {
+	names, _, err := f.list()
+	return names, err
+}
+
+// ListMetadata returns the public information of all the keys from the unencrypted JWE headers.
+// Files written by older versions or by iritacli only carry the creation time.
+func (f FileDAO) ListMetadata() ([]KeyMetadata, error) {
+	names, files, err := f.list()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([]KeyMetadata, 0, len(names))
+	for i, name := range names {
+		m, err := readFileMetadata(name, files[i])
+		if err != nil {
+			return nil, err
+		}
+		metadata = append(metadata, m)
+	}
+	return metadata, nil
+}
+
+// ReadMetadata reads the public information of the key from the unencrypted JWE header, the
+// private key is left out
+func (f FileDAO) ReadMetadata(name string) (KeyInfo, error) {
+	filename, err := f.filename(name)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	m, err := readFileMetadata(name, filename)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return KeyInfo{
+		Name:      m.Name,
+		PubKey:    m.PubKey,
+		Algo:      m.Algo,
+		CreatedAt: m.CreatedAt,
+		Type:      m.Type,
+		Address:   m.Address,
+	}, nil
+}
+
+// Rename moves a key to a new name and uses user password to verify permissions
+func (f FileDAO) Rename(oldName, newName, password string) error {
+	info, err := f.Read(oldName, password)
+	if err != nil {
+		return err
+	}
+	if f.Has(newName) {
+		return fmt.Errorf("name %s has exist", newName)
+	}
+
+	info.Name = newName
+	if err := f.write(newName, password, info); err != nil {
+		return err
+	}
+
+	filename, err := f.filename(oldName)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filename)
+}
+
+// Replace overwrites an existing key and uses user password to verify permissions, the new file
+// is moved over the existing one
+func (f FileDAO) Replace(name, password string, info KeyInfo) error {
+	if _, err := f.Read(name, password); err != nil {
+		return err
+	}
+	return f.write(name, password, info)
+}
+
+// ChangePassword encrypts a key again with the new password
+func (f FileDAO) ChangePassword(name, oldPassword, newPassword string) error {
+	if len(newPassword) == 0 {
+		return fmt.Errorf("no password")
+	}
+	info, err := f.Read(name, oldPassword)
+	if err != nil {
+		return err
+	}
+	return f.write(name, newPassword, info)
+}
+
+func (f *FileDAO) list() (names []string, files []string, err error) {
+	dir, err := f.resolveDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suffix := "." + infoSuffix
+	for _, entry := range entries {
+		filename := percent.Decode(entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(filename, suffix) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(filename, suffix))
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+
+	sort.Sort(byName{names, files})
+	return names, files, nil
+}
+
+type byName struct {
+	names []string
+	files []string
+}
+
+func (b byName) Len() int           { return len(b.names) }
+func (b byName) Less(i, j int) bool { return b.names[i] < b.names[j] }
+func (b byName) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+	b.files[i], b.files[j] = b.files[j], b.files[i]
+}
+
+// readFileMetadata decodes the protected header of a compact JWE without decrypting the payload
+func readFileMetadata(name, filename string) (KeyMetadata, error) {
+	bz, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+
+	parts := strings.SplitN(string(bz), ".", 2)
+	headerBz, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return KeyMetadata{}, fmt.Errorf("key %s: invalid header", name)
+	}
+
+	var headers map[string]interface{}
+	if err := json.Unmarshal(headerBz, &headers); err != nil {
+		return KeyMetadata{}, fmt.Errorf("key %s: invalid header", name)
+	}
+
+	info := KeyInfo{
+		Name:      name,
+		CreatedAt: parseCreated(headers[fileHeaderCreated]),
+	}
+	if algo, ok := headers[fileHeaderAlgo].(string); ok {
+		info.Algo = algo
+	}
+	if pubKey, ok := headers[fileHeaderPubKey].(string); ok {
+		if info.PubKey, err = base64.StdEncoding.DecodeString(pubKey); err != nil {
+			return KeyMetadata{}, fmt.Errorf("key %s: invalid pubkey", name)
+		}
+	}
+	if err := parseWatchOnly(headers, &info); err != nil {
+		return KeyMetadata{}, fmt.Errorf("key %s: %s", name, err.Error())
+	}
+	return newKeyMetadata(info), nil
+}
+
+// parseWatchOnly reads the type and the address of a watch-only key from the JWE header
+func parseWatchOnly(headers map[string]interface{}, info *KeyInfo) error {
+	keyType, ok := headers[fileHeaderType].(float64)
+	if !ok || KeyType(keyType) != TypeOffline {
+		return nil
+	}
+
+	info.Type = TypeOffline
+	if address, ok := headers[fileHeaderAddress].(string); ok {
+		bz, err := hex.DecodeString(address)
+		if err != nil {
+			return fmt.Errorf("invalid address")
+		}
+		info.Address = bz
+	}
+	return nil
+}
+
+// parseCreated parses the `created` header, written with time.Time.String() like the cosmos keyring does
+func parseCreated(created interface{}) time.Time {
+	s, ok := created.(string)
+	if !ok {
+		return time.Time{}
+	}
+	// drop the monotonic clock reading
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+	t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func (f *FileDAO) filename(key string) (string, error) {
 	dir, err := f.resolveDir()
 	if err != nil {
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/irisnet/core-sdk-go/common/crypto/hd"
+)
+
+// HDWalletSecret replaces the private key armor of an HD wallet entry: the entry holds a mnemonic
+// instead of a single key, and its PubKey is the one of the account 0/0
+type HDWalletSecret struct {
+	Mnemonic string `json:"mnemonic"`
+	CoinType uint32 `json:"coin_type"`
+	// Accounts are the derived accounts recorded in the wallet
+	Accounts []HDAccountPath `json:"accounts"`
+}
+
+// HDAccountPath locates an account of an HD wallet
+type HDAccountPath struct {
+	Account uint32 `json:"account"`
+	Index   uint32 `json:"index"`
+}
+
+// ParseHDWalletSecret returns false when the decrypted armor is a plain private key
+func ParseHDWalletSecret(armor string) (HDWalletSecret, bool) {
+	var secret HDWalletSecret
+	if err := json.Unmarshal([]byte(armor), &secret); err != nil || len(secret.Mnemonic) == 0 {
+		return HDWalletSecret{}, false
+	}
+	return secret, true
+}
+
+// String returns the armor stored in the KeyInfo
+func (s HDWalletSecret) String() string {
+	bz, _ := json.Marshal(s)
+	return string(bz)
+}
+
+// HDPath returns the BIP44 path of the account
+func (s HDWalletSecret) HDPath(account, index uint32) string {
+	return hd.CreateHDPath(s.CoinType, account, index).String()
+}
+
+// Derive returns the private key of the account
+func (s HDWalletSecret) Derive(algo string, account, index uint32) (crypto.PrivKey, error) {
+	derive, err := s.Deriver(algo)
+	if err != nil {
+		return nil, err
+	}
+	return derive(account, index)
+}
+
+// HDDeriver returns the private key of an account of a wallet
+type HDDeriver func(account, index uint32) (crypto.PrivKey, error)
+
+// Deriver computes the seed of the mnemonic once, the BIP39 key stretching is then shared by the
+// accounts derived with the HDDeriver
+func (s HDWalletSecret) Deriver(algo string) (HDDeriver, error) {
+	signingAlgo, err := hd.NewSigningAlgoFromString(algo)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hd.NewSeed(s.Mnemonic, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(account, index uint32) (crypto.PrivKey, error) {
+		derived, err := signingAlgo.DeriveFromSeed()(seed, s.HDPath(account, index))
+		if err != nil {
+			return nil, err
+		}
+		return signingAlgo.Generate()(derived), nil
+	}, nil
+}
+
+// AddAccounts records the accounts, keeping them sorted and unique
+func (s *HDWalletSecret) AddAccounts(paths ...HDAccountPath) {
+	seen := make(map[HDAccountPath]bool, len(s.Accounts))
+	for _, p := range s.Accounts {
+		seen[p] = true
+	}
+	for _, p := range paths {
+		if !seen[p] {
+			s.Accounts = append(s.Accounts, p)
+			seen[p] = true
+		}
+	}
+	sort.Slice(s.Accounts, func(i, j int) bool {
+		if s.Accounts[i].Account != s.Accounts[j].Account {
+			return s.Accounts[i].Account < s.Accounts[j].Account
+		}
+		return s.Accounts[i].Index < s.Accounts[j].Index
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	dbm "github.com/tendermint/tm-db"
 )
@@ -14,7 +17,9 @@ const (
 )
 
 var (
-	_ KeyDAO = LevelDBDAO{}
+	_ KeyDAO         = LevelDBDAO{}
+	_ KeyReplacer    = LevelDBDAO{}
+	_ MetadataReader = LevelDBDAO{}
 )
 
 type LevelDBDAO struct {
@@ -23,7 +28,7 @@ type LevelDBDAO struct {
 }
 
 // NewLevelDB initialize a keybase based on the configuration.
-// Use leveldb as storage
+// Use leveldb as storage, crypto defaults to AESGCM
 func NewLevelDB(rootDir string, crypto Crypto) (KeyDAO, error) {
 	db, err := dbm.NewGoLevelDB(keyDBName, filepath.Join(rootDir, "keys"))
 	if err != nil {
@@ -31,7 +36,7 @@ func NewLevelDB(rootDir string, crypto Crypto) (KeyDAO, error) {
 	}
 
 	if crypto == nil {
-		crypto = AES{}
+		crypto = AESGCM{}
 	}
 
 	levelDB := LevelDBDAO{
@@ -47,21 +52,29 @@ func (k LevelDBDAO) Write(name, password string, info KeyInfo) error {
 		return fmt.Errorf("name %s has exist", name)
 	}
 
-	privStr, err := k.Encrypt(info.PrivKeyArmor, password)
-	if err != nil {
-		return err
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = time.Now().UTC()
 	}
 
-	info.PrivKeyArmor = privStr
-
-	bz, err := json.Marshal(info)
+	bz, err := k.encode(password, info)
 	if err != nil {
 		return err
 	}
 	return k.db.SetSync(infoKey(name), bz)
 }
 
-// Read read a key information from the local store
+func (k LevelDBDAO) encode(password string, info KeyInfo) ([]byte, error) {
+	privStr, err := k.Encrypt(info.PrivKeyArmor, password)
+	if err != nil {
+		return nil, err
+	}
+	info.PrivKeyArmor = privStr
+	return json.Marshal(info)
+}
+
+// Read read a key information from the local store.
+// With the AESGCM crypto, a key still encrypted by the legacy AES is verified against its
+// public key then encrypted again with AESGCM.
 func (k LevelDBDAO) Read(name, password string) (store KeyInfo, err error) {
 	bz, err := k.db.Get(infoKey(name))
 	if bz == nil || err != nil {
@@ -73,6 +86,10 @@ func (k LevelDBDAO) Read(name, password string) (store KeyInfo, err error) {
 	}
 
 	if len(password) > 0 {
+		if k.migrates(store.PrivKeyArmor) {
+			return k.migrate(name, password, store)
+		}
+
 		privStr, err := k.Decrypt(store.PrivKeyArmor, password)
 		if err != nil {
 			return store, err
@@ -82,6 +99,31 @@ func (k LevelDBDAO) Read(name, password string) (store KeyInfo, err error) {
 	return
 }
 
+func (k LevelDBDAO) migrates(privKeyArmor string) bool {
+	_, ok := k.Crypto.(AESGCM)
+	return ok && !IsEnvelope(privKeyArmor)
+}
+
+func (k LevelDBDAO) migrate(name, password string, store KeyInfo) (KeyInfo, error) {
+	privStr, err := AES{}.Decrypt(store.PrivKeyArmor, password)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	store.PrivKeyArmor = privStr
+	if err := verifyKeyInfo(store); err != nil {
+		return KeyInfo{}, err
+	}
+
+	bz, err := k.encode(password, store)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	if err := k.db.SetSync(infoKey(name), bz); err != nil {
+		return KeyInfo{}, err
+	}
+	return store, nil
+}
+
 // ReadMetadata read a key information from the local store
 func (k LevelDBDAO) ReadMetadata(name string) (store KeyInfo, err error) {
 	bz, err := k.db.Get(infoKey(name))
@@ -97,7 +139,7 @@ func (k LevelDBDAO) ReadMetadata(name string) (store KeyInfo, err error) {
 
 // Delete delete a key from the local store
 func (k LevelDBDAO) Delete(name, password string) error {
-	_, err := k.Read(name, password)
+	_, err := k.readVerified(name, password)
 	if err != nil {
 		return err
 	}
@@ -113,6 +155,135 @@ func (k LevelDBDAO) Has(name string) bool {
 	return existed
 }
 
+// List returns the names of all the keys in alphabetical order
+func (k LevelDBDAO) List() ([]string, error) {
+	var names []string
+	err := k.iterate(func(name string, _ []byte) error {
+		names = append(names, name)
+		return nil
+	})
+	return names, err
+}
+
+// ListMetadata returns the public information of all the keys without decrypting them
+func (k LevelDBDAO) ListMetadata() ([]KeyMetadata, error) {
+	var metadata []KeyMetadata
+	err := k.iterate(func(name string, bz []byte) error {
+		var info KeyInfo
+		if err := json.Unmarshal(bz, &info); err != nil {
+			return fmt.Errorf("key %s: %s", name, err.Error())
+		}
+		info.Name = name
+		metadata = append(metadata, newKeyMetadata(info))
+		return nil
+	})
+	return metadata, err
+}
+
+// Rename moves a key to a new name in a single batch
+func (k LevelDBDAO) Rename(oldName, newName, password string) error {
+	info, err := k.readVerified(oldName, password)
+	if err != nil {
+		return err
+	}
+	if k.Has(newName) {
+		return fmt.Errorf("name %s has exist", newName)
+	}
+
+	info.Name = newName
+	bz, err := k.encode(password, info)
+	if err != nil {
+		return err
+	}
+
+	batch := k.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(infoKey(newName), bz); err != nil {
+		return err
+	}
+	if err := batch.Delete(infoKey(oldName)); err != nil {
+		return err
+	}
+	return batch.WriteSync()
+}
+
+// Replace overwrites an existing key and uses user password to verify permissions
+func (k LevelDBDAO) Replace(name, password string, info KeyInfo) error {
+	if _, err := k.readVerified(name, password); err != nil {
+		return err
+	}
+
+	bz, err := k.encode(password, info)
+	if err != nil {
+		return err
+	}
+	return k.db.SetSync(infoKey(name), bz)
+}
+
+// ChangePassword encrypts a key again with the new password
+func (k LevelDBDAO) ChangePassword(name, oldPassword, newPassword string) error {
+	info, err := k.readVerified(name, oldPassword)
+	if err != nil {
+		return err
+	}
+
+	bz, err := k.encode(newPassword, info)
+	if err != nil {
+		return err
+	}
+	return k.db.SetSync(infoKey(name), bz)
+}
+
+func (k LevelDBDAO) readVerified(name, password string) (KeyInfo, error) {
+	if !k.Has(name) {
+		return KeyInfo{}, fmt.Errorf("name %s not exist", name)
+	}
+	if len(password) == 0 {
+		return KeyInfo{}, fmt.Errorf("no password")
+	}
+	info, err := k.Read(name, password)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return info, verifyKeyInfo(info)
+}
+
+func (k LevelDBDAO) iterate(fn func(name string, bz []byte) error) error {
+	itr, err := k.db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	suffix := "." + infoSuffix
+	type entry struct {
+		name string
+		bz   []byte
+	}
+	var entries []entry
+	for ; itr.Valid(); itr.Next() {
+		key := string(itr.Key())
+		if !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		entries = append(entries, entry{name: strings.TrimSuffix(key, suffix), bz: itr.Value()})
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+
+	// the suffix breaks the byte order of the names, e.g. "a.b.info" < "a.info"
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	for _, e := range entries {
+		if err := fn(e.name, e.bz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func infoKey(name string) []byte {
 	return []byte(fmt.Sprintf("%s.%s", name, infoSuffix))
 }
@@ -1,40 +1,308 @@
 package store
 
-// Use memory as storage, use with caution in build environment
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+const memorySaltLen = 16
+
+var (
+	_ KeyDAO         = MemoryDAO{}
+	_ KeyReplacer    = MemoryDAO{}
+	_ MetadataReader = MemoryDAO{}
+)
+
+// MemoryDAO keeps the keys in memory, suitable for tests and ephemeral signers.
+// It is safe for concurrent use and enforces the password of every key. The private keys are
+// encrypted at rest, unless the DAO is created by NewPlaintextMemory, which keeps them in plaintext
+// and checks the password against a salted hash.
 type MemoryDAO struct {
-	store map[string]KeyInfo
+	mtx   *sync.RWMutex
+	store map[string]MemoryEntry
 	Crypto
 }
 
+// MemoryEntry is a key held by a MemoryDAO
+type MemoryEntry struct {
+	// Info holds the private key, encrypted unless the MemoryDAO is plaintext
+	Info KeyInfo `json:"info"`
+	// Salt and Verifier check the password of keys kept in plaintext
+	Salt     []byte `json:"salt,omitempty"`
+	Verifier []byte `json:"verifier,omitempty"`
+}
+
+// MemorySnapshot is a copy of the content of a MemoryDAO, it can be serialized to preload fixtures.
+// A snapshot must be restored into a MemoryDAO using the same Crypto.
+type MemorySnapshot struct {
+	Entries map[string]MemoryEntry `json:"entries"`
+}
+
+// NewMemory returns an empty MemoryDAO encrypting the private keys with crypto, AESGCM by default
 func NewMemory(crypto Crypto) MemoryDAO {
 	if crypto == nil {
-		crypto = AES{}
+		crypto = AESGCM{}
 	}
 	return MemoryDAO{
-		store:  make(map[string]KeyInfo),
+		mtx:    new(sync.RWMutex),
+		store:  make(map[string]MemoryEntry),
 		Crypto: crypto,
 	}
 }
+
+// NewPlaintextMemory returns an empty MemoryDAO keeping the private keys in plaintext, for tests
+// where the cost of the key derivation matters more than the secrecy of the keys
+func NewPlaintextMemory() MemoryDAO {
+	return MemoryDAO{
+		mtx:   new(sync.RWMutex),
+		store: make(map[string]MemoryEntry),
+	}
+}
+
 func (m MemoryDAO) Write(name, password string, store KeyInfo) error {
-	m.store[name] = store
+	if len(password) == 0 {
+		return fmt.Errorf("no password")
+	}
+	if store.CreatedAt.IsZero() {
+		store.CreatedAt = time.Now().UTC()
+	}
+
+	entry, err := m.seal(password, store)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.store[name]; ok {
+		return fmt.Errorf("name %s has exist", name)
+	}
+	m.store[name] = entry
 	return nil
 }
 
 func (m MemoryDAO) Read(name, password string) (KeyInfo, error) {
-	return m.store[name], nil
+	m.mtx.RLock()
+	entry, ok := m.store[name]
+	m.mtx.RUnlock()
+	if !ok {
+		return KeyInfo{}, fmt.Errorf("name %s not exist", name)
+	}
+	return m.open(password, entry)
 }
 
-// ReadMetadata read a key information from the local store
+// ReadMetadata read a key information from the local store, the private key is left out
 func (m MemoryDAO) ReadMetadata(name string) (store KeyInfo, err error) {
-	return m.store[name], nil
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	entry, ok := m.store[name]
+	if !ok {
+		return store, fmt.Errorf("name %s not exist", name)
+	}
+	store = entry.Info
+	store.PrivKeyArmor = ""
+	return store, nil
 }
 
 func (m MemoryDAO) Delete(name, password string) error {
+	if _, err := m.Read(name, password); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
 	delete(m.store, name)
 	return nil
 }
 
 func (m MemoryDAO) Has(name string) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
 	_, ok := m.store[name]
 	return ok
 }
+
+// List returns the names of all the keys in alphabetical order
+func (m MemoryDAO) List() ([]string, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.names(), nil
+}
+
+// ListMetadata returns the public information of all the keys without decrypting them
+func (m MemoryDAO) ListMetadata() ([]KeyMetadata, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	names := m.names()
+	metadata := make([]KeyMetadata, 0, len(names))
+	for _, name := range names {
+		info := m.store[name].Info
+		info.Name = name
+		metadata = append(metadata, newKeyMetadata(info))
+	}
+	return metadata, nil
+}
+
+// Rename moves a key to a new name and uses user password to verify permissions
+func (m MemoryDAO) Rename(oldName, newName, password string) error {
+	if _, err := m.Read(oldName, password); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	entry, ok := m.store[oldName]
+	if !ok {
+		return fmt.Errorf("name %s not exist", oldName)
+	}
+	if _, ok := m.store[newName]; ok {
+		return fmt.Errorf("name %s has exist", newName)
+	}
+	entry.Info.Name = newName
+	m.store[newName] = entry
+	delete(m.store, oldName)
+	return nil
+}
+
+// Replace overwrites an existing key and uses user password to verify permissions
+func (m MemoryDAO) Replace(name, password string, info KeyInfo) error {
+	if _, err := m.Read(name, password); err != nil {
+		return err
+	}
+	entry, err := m.seal(password, info)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.store[name]; !ok {
+		return fmt.Errorf("name %s not exist", name)
+	}
+	m.store[name] = entry
+	return nil
+}
+
+// ChangePassword encrypts a key again with the new password
+func (m MemoryDAO) ChangePassword(name, oldPassword, newPassword string) error {
+	if len(newPassword) == 0 {
+		return fmt.Errorf("no password")
+	}
+	info, err := m.Read(name, oldPassword)
+	if err != nil {
+		return err
+	}
+	entry, err := m.seal(newPassword, info)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.store[name]; !ok {
+		return fmt.Errorf("name %s not exist", name)
+	}
+	m.store[name] = entry
+	return nil
+}
+
+// Snapshot returns a copy of all the keys, encrypted ones stay encrypted
+func (m MemoryDAO) Snapshot() MemorySnapshot {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	snapshot := MemorySnapshot{Entries: make(map[string]MemoryEntry, len(m.store))}
+	for name, entry := range m.store {
+		snapshot.Entries[name] = entry.copy()
+	}
+	return snapshot
+}
+
+// Restore replaces all the keys by the content of the snapshot
+func (m MemoryDAO) Restore(snapshot MemorySnapshot) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for name := range m.store {
+		delete(m.store, name)
+	}
+	for name, entry := range snapshot.Entries {
+		m.store[name] = entry.copy()
+	}
+}
+
+func (m MemoryDAO) names() []string {
+	names := make([]string, 0, len(m.store))
+	for name := range m.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m MemoryDAO) seal(password string, info KeyInfo) (MemoryEntry, error) {
+	if m.Crypto != nil {
+		privStr, err := m.Encrypt(info.PrivKeyArmor, password)
+		if err != nil {
+			return MemoryEntry{}, err
+		}
+		info.PrivKeyArmor = privStr
+		return MemoryEntry{Info: info}, nil
+	}
+
+	salt := make([]byte, memorySaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return MemoryEntry{}, err
+	}
+	return MemoryEntry{
+		Info:     info,
+		Salt:     salt,
+		Verifier: passwordVerifier(salt, password),
+	}, nil
+}
+
+func (m MemoryDAO) open(password string, entry MemoryEntry) (KeyInfo, error) {
+	if len(password) == 0 {
+		return KeyInfo{}, fmt.Errorf("no password")
+	}
+
+	info := entry.Info
+	if m.Crypto == nil {
+		if subtle.ConstantTimeCompare(passwordVerifier(entry.Salt, password), entry.Verifier) != 1 {
+			return KeyInfo{}, fmt.Errorf("wrong password")
+		}
+		return info, nil
+	}
+
+	privStr, err := m.Decrypt(info.PrivKeyArmor, password)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	info.PrivKeyArmor = privStr
+	// ciphers without authentication decrypt with any password
+	if err := verifyKeyInfo(info); err != nil {
+		return KeyInfo{}, err
+	}
+	return info, nil
+}
+
+func passwordVerifier(salt []byte, password string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(password))
+	return h.Sum(nil)
+}
+
+func (e MemoryEntry) copy() MemoryEntry {
+	e.Info.PubKey = append([]byte(nil), e.Info.PubKey...)
+	e.Salt = append([]byte(nil), e.Salt...)
+	e.Verifier = append([]byte(nil), e.Verifier...)
+	return e
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cryptocodec "github.com/irisnet/core-sdk-go/common/crypto/codec"
+	"github.com/irisnet/core-sdk-go/common/crypto/keys/secp256k1"
+)
+
+func newKeyInfo(name string) KeyInfo {
+	priv := secp256k1.GenPrivKey()
+	return KeyInfo{
+		Name:         name,
+		PubKey:       cryptocodec.MarshalPubkey(priv.PubKey()),
+		PrivKeyArmor: string(cryptocodec.MarshalPrivKey(priv)),
+		Algo:         "secp256k1",
+	}
+}
+
+func TestKeyDAOLifecycle(t *testing.T) {
+	levelDB, err := NewLevelDB(t.TempDir(), nil)
+	require.NoError(t, err)
+
+	daos := map[string]KeyDAO{
+		"file":             NewFileDAO(t.TempDir()),
+		"leveldb":          levelDB,
+		"memory":           NewPlaintextMemory(),
+		"encrypted memory": NewMemory(AESGCM{ScryptLogN: 10}),
+	}
+	for kind, dao := range daos {
+		t.Run(kind, func(t *testing.T) {
+			bob, alice := newKeyInfo("bob"), newKeyInfo("alice")
+			require.NoError(t, dao.Write("bob", "12345678", bob))
+			require.NoError(t, dao.Write("alice", "12345678", alice))
+
+			names, err := dao.List()
+			require.NoError(t, err)
+			require.Equal(t, []string{"alice", "bob"}, names)
+
+			metadata, err := dao.ListMetadata()
+			require.NoError(t, err)
+			require.Len(t, metadata, 2)
+			require.Equal(t, "bob", metadata[1].Name)
+			require.Equal(t, bob.PubKey, metadata[1].PubKey)
+			require.Equal(t, "secp256k1", metadata[1].Algo)
+			require.False(t, metadata[1].CreatedAt.IsZero())
+			require.Equal(t, time.UTC, metadata[1].CreatedAt.Location())
+
+			pubKey, err := PubKeyFromBytes(bob.PubKey)
+			require.NoError(t, err)
+			require.Equal(t, pubKey.Address(), metadata[1].Address)
+
+			require.Error(t, dao.Rename("bob", "alice", "12345678"))
+			require.NoError(t, dao.Rename("bob", "carol", "12345678"))
+			require.False(t, dao.Has("bob"))
+
+			require.NoError(t, dao.ChangePassword("carol", "12345678", "87654321"))
+			info, err := dao.Read("carol", "87654321")
+			require.NoError(t, err)
+			require.Equal(t, "carol", info.Name)
+			require.Equal(t, bob.PrivKeyArmor, info.PrivKeyArmor)
+			require.Equal(t, metadata[1].CreatedAt.Unix(), info.CreatedAt.Unix())
+
+			require.Error(t, dao.ChangePassword("carol", "12345678", "00000000"))
+			require.Error(t, dao.Rename("carol", "dave", "12345678"))
+
+			replacer := dao.(KeyReplacer)
+			dave := newKeyInfo("carol")
+			require.Error(t, replacer.Replace("carol", "12345678", dave))
+			require.Error(t, replacer.Replace("dave", "87654321", dave))
+			require.NoError(t, replacer.Replace("carol", "87654321", dave))
+			info, err = dao.Read("carol", "87654321")
+			require.NoError(t, err)
+			require.Equal(t, dave.PrivKeyArmor, info.PrivKeyArmor)
+		})
+	}
+}
+
+func TestAESGCM(t *testing.T) {
+	crypto := AESGCM{ScryptLogN: 10}
+
+	cipherText, err := crypto.Encrypt("secret", "12345678")
+	require.NoError(t, err)
+	require.True(t, IsEnvelope(cipherText))
+
+	other, err := crypto.Encrypt("secret", "12345678")
+	require.NoError(t, err)
+	require.NotEqual(t, cipherText, other, "salt and nonce must be random")
+
+	plainText, err := crypto.Decrypt(cipherText, "12345678")
+	require.NoError(t, err)
+	require.Equal(t, "secret", plainText)
+
+	_, err = crypto.Decrypt(cipherText, "87654321")
+	require.Error(t, err)
+
+	// parameters are read from the envelope
+	plainText, err = AESGCM{}.Decrypt(cipherText, "12345678")
+	require.NoError(t, err)
+	require.Equal(t, "secret", plainText)
+}
+
+func TestAESGCMScryptBounds(t *testing.T) {
+	cipherText, err := AESGCM{ScryptLogN: 10}.Encrypt("secret", "12345678")
+	require.NoError(t, err)
+	envelope, err := base64.URLEncoding.DecodeString(strings.TrimPrefix(cipherText, envelopeV1Prefix))
+	require.NoError(t, err)
+
+	// tampered parameters are rejected before scrypt runs
+	for _, params := range [][3]byte{{30, 8, 1}, {20, 16, 1}, {10, 8, 8}, {10, 0, 1}} {
+		tampered := append([]byte{}, envelope...)
+		copy(tampered, params[:])
+		_, err = AESGCM{}.Decrypt(envelopeV1Prefix+base64.URLEncoding.EncodeToString(tampered), "12345678")
+		require.EqualError(t, err, "invalid scrypt parameters")
+	}
+
+	_, err = AESGCM{ScryptLogN: 21}.Encrypt("secret", "12345678")
+	require.Error(t, err)
+}
+
+func TestLevelDBMigration(t *testing.T) {
+	dir := t.TempDir()
+	legacy, err := NewLevelDB(dir, AES{})
+	require.NoError(t, err)
+
+	info := newKeyInfo("bob")
+	require.NoError(t, legacy.Write("bob", "12345678", info))
+	require.NoError(t, legacy.(LevelDBDAO).db.Close())
+
+	dao, err := NewLevelDB(dir, AESGCM{ScryptLogN: 10})
+	require.NoError(t, err)
+	levelDB := dao.(LevelDBDAO)
+
+	// a wrong password must neither succeed nor migrate the key
+	_, err = dao.Read("bob", "87654321")
+	require.Error(t, err)
+	stored, err := levelDB.ReadMetadata("bob")
+	require.NoError(t, err)
+	require.False(t, IsEnvelope(stored.PrivKeyArmor))
+
+	read, err := dao.Read("bob", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, info.PrivKeyArmor, read.PrivKeyArmor)
+
+	stored, err = levelDB.ReadMetadata("bob")
+	require.NoError(t, err)
+	require.True(t, IsEnvelope(stored.PrivKeyArmor))
+
+	read, err = dao.Read("bob", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, info.PrivKeyArmor, read.PrivKeyArmor)
+}
+
+func TestMemoryDAO(t *testing.T) {
+	dao := NewMemory(AESGCM{ScryptLogN: 10})
+	info := newKeyInfo("bob")
+	require.NoError(t, dao.Write("bob", "12345678", info))
+
+	_, err := dao.Read("bob", "87654321")
+	require.Error(t, err)
+	require.Error(t, dao.Delete("bob", "87654321"))
+
+	stored, err := dao.ReadMetadata("bob")
+	require.NoError(t, err)
+	require.Empty(t, stored.PrivKeyArmor)
+	require.NotEqual(t, info.PrivKeyArmor, dao.Snapshot().Entries["bob"].Info.PrivKeyArmor)
+
+	snapshot := dao.Snapshot()
+	require.NoError(t, dao.Delete("bob", "12345678"))
+	require.False(t, dao.Has("bob"))
+
+	dao.Restore(snapshot)
+	read, err := dao.Read("bob", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, info.PrivKeyArmor, read.PrivKeyArmor)
+
+	// the default crypto encrypts
+	encrypted := NewMemory(nil)
+	require.NoError(t, encrypted.Write("bob", "12345678", info))
+	require.True(t, IsEnvelope(encrypted.Snapshot().Entries["bob"].Info.PrivKeyArmor))
+
+	plain := NewPlaintextMemory()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("key%d", i)
+			require.NoError(t, plain.Write(name, "12345678", newKeyInfo(name)))
+			_, err := plain.Read(name, "12345678")
+			require.NoError(t, err)
+			_, err = plain.ListMetadata()
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	names, err := plain.List()
+	require.NoError(t, err)
+	require.Len(t, names, 16)
+}
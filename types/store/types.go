@@ -1,10 +1,13 @@
 package store
 
 import (
+	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/tendermint/tendermint/crypto"
 
+	cryptocodec "github.com/irisnet/core-sdk-go/common/crypto/codec"
 	"github.com/irisnet/core-sdk-go/common/crypto/hd"
 )
 
@@ -18,14 +21,47 @@ type KeyType uint
 // Info KeyTypes
 const (
 	TypeLocal KeyType = 0
+	// TypeOffline is a watch-only key, it holds a public key or only an address and can't sign
+	TypeOffline KeyType = 2
 )
 
+// WatchOnlyVerifier is the PrivKeyArmor of the watch-only keys, it is encrypted like a private key
+// so a wrong password is detected by the ciphers without authentication too
+const WatchOnlyVerifier = "watch-only"
+
 // KeyInfo saves the basic information of the key
 type KeyInfo struct {
 	Name         string `json:"name"`
 	PubKey       []byte `json:"pubkey"`
 	PrivKeyArmor string `json:"priv_key_armor"`
 	Algo         string `json:"algo"`
+	// CreatedAt is set by the KeyDAO on the first write, zero for keys written by older versions
+	CreatedAt time.Time `json:"created_at"`
+	// Type is TypeOffline for the watch-only keys, their PrivKeyArmor is WatchOnlyVerifier
+	Type KeyType `json:"type,omitempty"`
+	// Address of a watch-only key imported without its public key
+	Address crypto.Address `json:"address,omitempty"`
+}
+
+// IsWatchOnly returns whether the key has no private key
+func (info KeyInfo) IsWatchOnly() bool {
+	return info.Type == TypeOffline
+}
+
+// Metadata returns the public information of the key
+func (info KeyInfo) Metadata() KeyMetadata {
+	return newKeyMetadata(info)
+}
+
+// KeyMetadata is the public information of a stored key, readable without the password
+type KeyMetadata struct {
+	Name string `json:"name"`
+	// Address is derived from PubKey, both are empty for legacy file keys whose public key is encrypted
+	Address   crypto.Address `json:"address"`
+	PubKey    []byte         `json:"pubkey"`
+	Algo      string         `json:"algo"`
+	CreatedAt time.Time      `json:"created_at"`
+	Type      KeyType        `json:"type"`
 }
 
 type KeyDAO interface {
@@ -40,6 +76,31 @@ type KeyDAO interface {
 
 	// Has returns whether the specified user name exists
 	Has(name string) bool
+
+	// List returns the names of all the keys in alphabetical order
+	List() ([]string, error)
+
+	// ListMetadata returns the public information of all the keys without decrypting them
+	ListMetadata() ([]KeyMetadata, error)
+
+	// Rename moves a key to a new name and uses user password to verify permissions
+	Rename(oldName, newName, password string) error
+
+	// ChangePassword encrypts a key again with the new password
+	ChangePassword(name, oldPassword, newPassword string) error
+}
+
+// KeyReplacer is implemented by the KeyDAOs able to overwrite a key in one step, the existing entry
+// stays in place until the new one is written
+type KeyReplacer interface {
+	// Replace overwrites an existing key and uses user password to verify permissions
+	Replace(name, password string, store KeyInfo) error
+}
+
+// MetadataReader is implemented by the KeyDAOs able to read the information of one key without
+// the password, the private key is left out or still encrypted
+type MetadataReader interface {
+	ReadMetadata(name string) (KeyInfo, error)
 }
 
 type Crypto interface {
@@ -95,6 +156,59 @@ func (i localInfo) GetPath() (*hd.BIP44Params, error) {
 	return nil, fmt.Errorf("BIP44 Paths are not available for this type")
 }
 
+// newKeyMetadata builds the metadata of a key, the private key is never read
+func newKeyMetadata(info KeyInfo) KeyMetadata {
+	metadata := KeyMetadata{
+		Name:      info.Name,
+		PubKey:    info.PubKey,
+		Algo:      info.Algo,
+		CreatedAt: info.CreatedAt,
+		Type:      info.Type,
+	}
+	if pubKey, err := PubKeyFromBytes(info.PubKey); err == nil && pubKey != nil {
+		metadata.Address = pubKey.Address()
+	} else if len(info.Address) > 0 {
+		metadata.Address = info.Address
+	}
+	return metadata
+}
+
+// verifyKeyInfo checks that the decrypted private key matches the public key, AES-CFB
+// decrypts with any password so this is the only way to detect a wrong password
+func verifyKeyInfo(info KeyInfo) error {
+	if info.IsWatchOnly() {
+		// nothing secret to protect, the password only guards the changes of the entry
+		if info.PrivKeyArmor != WatchOnlyVerifier {
+			return fmt.Errorf("wrong password")
+		}
+		return nil
+	}
+
+	var privKey crypto.PrivKey
+	if secret, ok := ParseHDWalletSecret(info.PrivKeyArmor); ok {
+		derived, err := secret.Derive(info.Algo, 0, 0)
+		if err != nil {
+			return fmt.Errorf("wrong password")
+		}
+		privKey = derived
+	} else {
+		decoded, err := cryptocodec.PrivKeyFromBytes([]byte(info.PrivKeyArmor))
+		if err != nil {
+			return fmt.Errorf("wrong password")
+		}
+		privKey = decoded
+	}
+
+	pubKey, err := PubKeyFromBytes(info.PubKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(privKey.PubKey().Address(), pubKey.Address()) {
+		return fmt.Errorf("wrong password")
+	}
+	return nil
+}
+
 // encoding info
 func marshalInfo(i Info) []byte {
 	return cdc.MustMarshalBinaryLengthPrefixed(i)
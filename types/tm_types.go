@@ -19,6 +19,7 @@ type (
 	SignClient    = tmclient.SignClient
 	StatusClient  = tmclient.StatusClient
 	NetworkClient = tmclient.NetworkClient
+	MempoolClient = tmclient.MempoolClient
 	Header        = tmtypes.Header
 	Pair          = kv.Pair
 
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TxDirection classifies a tx from the point of view of one account
+type TxDirection string
+
+const (
+	TxDirectionIncoming TxDirection = "incoming"
+	TxDirectionOutgoing TxDirection = "outgoing"
+	// TxDirectionNone is used when the tx did not change the balance of the account
+	TxDirectionNone TxDirection = "none"
+)
+
+// DefaultTxHistoryEvents are the event attributes searched to build the history of an account
+var DefaultTxHistoryEvents = []string{
+	"message.sender",
+	"transfer.recipient",
+	"transfer.sender",
+	"delegate.delegator",
+	"tx.fee_payer",
+	"use_feegrant.granter",
+}
+
+// TxHistoryRequest selects a page of the history of an account
+type TxHistoryRequest struct {
+	// Cursor returned by the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	// Limit is the page size, at most 100
+	Limit int `json:"limit"`
+	// Order of the history by height, defaults to desc (newest first)
+	Order TxSearchOrder `json:"order"`
+	// Events are the `type.attribute` keys matched against the address, defaults to DefaultTxHistoryEvents
+	Events []string `json:"events"`
+}
+
+// TxHistoryEntry is a tx involving the account
+type TxHistoryEntry struct {
+	ResultQueryTx
+	Direction TxDirection `json:"direction"`
+	// Delta is the net change of the account balance, fees included
+	Delta CoinDeltas `json:"delta"`
+}
+
+// TxHistory is a page of the history of an account
+type TxHistory struct {
+	Entries []TxHistoryEntry `json:"entries"`
+	// NextCursor is empty when there are no more entries
+	NextCursor string `json:"next_cursor"`
+}
+
+// TxHistoryCursor is the position of a tx in the chain, used to resume a history query
+type TxHistoryCursor struct {
+	Height int64
+	Index  uint32
+}
+
+func (c TxHistoryCursor) String() string {
+	return fmt.Sprintf("%d:%d", c.Height, c.Index)
+}
+
+// ParseTxHistoryCursor parses a cursor in the form `height:index`
+func ParseTxHistoryCursor(cursor string) (TxHistoryCursor, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 {
+		return TxHistoryCursor{}, fmt.Errorf("invalid cursor %s", cursor)
+	}
+
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || height <= 0 {
+		return TxHistoryCursor{}, fmt.Errorf("invalid cursor height %s", parts[0])
+	}
+
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return TxHistoryCursor{}, fmt.Errorf("invalid cursor index %s", parts[1])
+	}
+	return TxHistoryCursor{Height: height, Index: uint32(index)}, nil
+}
+
+// Before returns whether the position comes strictly before the other one in the given order
+func (c TxHistoryCursor) Before(other TxHistoryCursor, order TxSearchOrder) bool {
+	if order == TxSearchOrderDesc {
+		return c.Height > other.Height || (c.Height == other.Height && c.Index > other.Index)
+	}
+	return c.Height < other.Height || (c.Height == other.Height && c.Index < other.Index)
+}
+
+// NewTxHistoryEntry classifies the tx for the address from its coin_spent/coin_received events
+func NewTxHistoryEntry(address string, tx ResultQueryTx) (TxHistoryEntry, error) {
+	delta, err := ParseCoinDeltas(address, tx.Result.Events)
+	if err != nil {
+		return TxHistoryEntry{}, err
+	}
+
+	direction := TxDirectionNone
+	if delta.IsAnyNegative() {
+		direction = TxDirectionOutgoing
+	} else if delta.IsAnyPositive() {
+		direction = TxDirectionIncoming
+	}
+
+	return TxHistoryEntry{
+		ResultQueryTx: tx,
+		Direction:     direction,
+		Delta:         delta,
+	}, nil
+}
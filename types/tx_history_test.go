@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTxHistoryEntry(t *testing.T) {
+	events := StringEvents{
+		{
+			Type: EventTypeCoinSpent,
+			Attributes: []Attribute{
+				{Key: AttributeKeySpender, Value: "alice"},
+				{Key: AttributeKeyAmount, Value: "5uiris"},
+				{Key: AttributeKeySpender, Value: "alice"},
+				{Key: AttributeKeyAmount, Value: "100uiris,3uatom"},
+			},
+		},
+		{
+			Type: EventTypeCoinReceived,
+			Attributes: []Attribute{
+				{Key: AttributeKeyReceiver, Value: "fee_collector"},
+				{Key: AttributeKeyAmount, Value: "5uiris"},
+				{Key: AttributeKeyReceiver, Value: "bob"},
+				{Key: AttributeKeyAmount, Value: "100uiris,3uatom"},
+			},
+		},
+	}
+	tx := ResultQueryTx{Hash: "ABCD", Height: 10, Result: TxResult{Events: events}}
+
+	entry, err := NewTxHistoryEntry("alice", tx)
+	require.NoError(t, err)
+	require.Equal(t, TxDirectionOutgoing, entry.Direction)
+	require.Equal(t, NewInt(-105), entry.Delta.AmountOf("uiris"))
+	require.Equal(t, NewInt(-3), entry.Delta.AmountOf("uatom"))
+
+	entry, err = NewTxHistoryEntry("bob", tx)
+	require.NoError(t, err)
+	require.Equal(t, TxDirectionIncoming, entry.Direction)
+	require.Equal(t, CoinDeltas{{"uatom", NewInt(3)}, {"uiris", NewInt(100)}}, entry.Delta)
+
+	entry, err = NewTxHistoryEntry("carol", tx)
+	require.NoError(t, err)
+	require.Equal(t, TxDirectionNone, entry.Direction)
+	require.Empty(t, entry.Delta)
+}
+
+func TestTxHistoryCursor(t *testing.T) {
+	c, err := ParseTxHistoryCursor("100:2")
+	require.NoError(t, err)
+	require.Equal(t, TxHistoryCursor{Height: 100, Index: 2}, c)
+	require.Equal(t, "100:2", c.String())
+
+	require.True(t, c.Before(TxHistoryCursor{Height: 100, Index: 1}, TxSearchOrderDesc))
+	require.True(t, c.Before(TxHistoryCursor{Height: 101, Index: 0}, TxSearchOrderAsc))
+	require.False(t, c.Before(c, TxSearchOrderAsc))
+
+	_, err = ParseTxHistoryCursor("100")
+	require.Error(t, err)
+	_, err = ParseTxHistoryCursor("-1:0")
+	require.Error(t, err)
+}